@@ -15,7 +15,8 @@ import (
 
 func main() {
 
-	fmt.Println(os.Args)
+	// On stderr, so that commands which write their results to stdout can be piped.
+	fmt.Fprintln(os.Stderr, os.Args)
 
 	cmd.Execute()
 
@@ -0,0 +1,92 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+// A checksum which maven repositories expect to find next to each artifact file, in a file
+// named after the artifact file with the extension added. eg. pom.xml.sha1
+type mavenChecksumAlgorithm struct {
+	Extension string
+	NewHash   func() hash.Hash
+}
+
+var mavenChecksumAlgorithms = []mavenChecksumAlgorithm{
+	{Extension: "md5", NewHash: md5.New},
+	{Extension: "sha1", NewHash: sha1.New},
+	{Extension: "sha256", NewHash: sha256.New},
+	{Extension: "sha512", NewHash: sha512.New},
+}
+
+// Returns true if the file is one of the checksum files of another file.
+func isMavenChecksumFile(fileName string) bool {
+	isChecksum := false
+	for _, algorithm := range mavenChecksumAlgorithms {
+		if strings.HasSuffix(fileName, "."+algorithm.Extension) {
+			isChecksum = true
+			break
+		}
+	}
+	return isChecksum
+}
+
+// Reads a file once, working out every checksum of it and its size. The checksums are returned as lower
+// case hex, keyed by the extension of their checksum file.
+func calculateMavenChecksums(fileSystem utils.FileSystem, filePath string) (map[string]string, int64, error) {
+	var size int64
+	checksums := make(map[string]string)
+
+	hashes := make([]hash.Hash, len(mavenChecksumAlgorithms))
+	writers := make([]io.Writer, len(mavenChecksumAlgorithms))
+	for index, algorithm := range mavenChecksumAlgorithms {
+		hashes[index] = algorithm.NewHash()
+		writers[index] = hashes[index]
+	}
+
+	file, err := fileSystem.Open(filePath)
+	if err == nil {
+		defer file.Close()
+		size, err = io.Copy(io.MultiWriter(writers...), file)
+	}
+
+	if err == nil {
+		for index, algorithm := range mavenChecksumAlgorithms {
+			checksums[algorithm.Extension] = hex.EncodeToString(hashes[index].Sum(nil))
+		}
+	} else {
+		err = fmt.Errorf("unable to calculate the checksums of %v - %v", filePath, err.Error())
+	}
+
+	return checksums, size, err
+}
+
+// Checks that the contents of a checksum file which is already in the local repository matches the
+// checksum of its artifact file. Some tools write the file name after the checksum, so only the first
+// word of the file is compared. The contents of the checksum file are returned.
+func verifyMavenChecksumFile(fileSystem utils.FileSystem, checksumFilePath string, expectedChecksum string) (string, error) {
+	contents, err := fileSystem.ReadTextFile(checksumFilePath)
+	if err == nil {
+		fields := strings.Fields(contents)
+		if len(fields) == 0 || !strings.EqualFold(fields[0], expectedChecksum) {
+			err = fmt.Errorf("checksum file %v does not match its artifact, expected %v", checksumFilePath, expectedChecksum)
+		}
+	} else {
+		err = fmt.Errorf("unable to read checksum file %v - %v", checksumFilePath, err.Error())
+	}
+	return contents, err
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+// A file which a deploy uploads to the remote repository.
+type mavenDeployFile struct {
+	Url string `json:"url"`
+	// Where the file is in the local repository, or "" if the deploy generates it.
+	LocalPath string `json:"localPath,omitempty"`
+	Size      int64  `json:"size"`
+	// The checksums of an artifact file, keyed by the extension of their checksum file. Checksum files have none.
+	Checksums map[string]string `json:"checksums,omitempty"`
+
+	// The contents of a generated file.
+	contents []byte
+}
+
+// Returns a function which opens the file for uploading, from the local repository or the generated contents.
+func (file mavenDeployFile) opener(fileSystem utils.FileSystem) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		if file.LocalPath == "" {
+			return io.NopCloser(bytes.NewReader(file.contents)), nil
+		}
+		return fileSystem.Open(file.LocalPath)
+	}
+}
+
+// What a deploy would do, without doing it.
+type mavenDeployPlan struct {
+	Repository string                    `json:"repository"`
+	Artifacts  []mavenDeployPlanArtifact `json:"artifacts"`
+	FileCount  int                       `json:"fileCount"`
+	TotalSize  int64                     `json:"totalSize"`
+}
+
+type mavenDeployPlanArtifact struct {
+	GroupId    string            `json:"groupId"`
+	ArtifactId string            `json:"artifactId"`
+	Version    string            `json:"version"`
+	Files      []mavenDeployFile `json:"files"`
+	// The maven-metadata.xml files which are merged with the ones in the remote repository, so their
+	// contents are only known when deploying. Each is uploaded with its checksum files.
+	MergedMetadataUrls []string `json:"mergedMetadataUrls"`
+}
+
+// Works out the files to upload for a version of an artifact. Each file in the version folder is followed by each of
+// its checksum files. Checksum files which are already in the local repository are checked against their file, and
+// the missing ones are generated.
+func planArtifactFiles(
+	fileSystem utils.FileSystem,
+	mavenRepository string,
+	localRepository string,
+	artifactVersionPath string) ([]mavenDeployFile, error) {
+
+	var files []mavenDeployFile
+
+	versionArtifacts, err := fileSystem.ReadDir(artifactVersionPath) //doesn't return err if dir doesn't exist
+	if err == nil {
+
+		localFileNames := make(map[string]bool)
+		for _, artifactFile := range versionArtifacts {
+			localFileNames[artifactFile.Name()] = true
+		}
+		isSnapshot := isMavenSnapshotVersion(path.Base(artifactVersionPath))
+
+		for _, artifactFile := range versionArtifacts {
+			fileName := artifactFile.Name()
+
+			// Checksum files are deployed along with the file they are the checksum of
+			if isMavenChecksumFile(fileName) && localFileNames[strings.TrimSuffix(fileName, path.Ext(fileName))] {
+				continue
+			}
+
+			// The metadata of a SNAPSHOT version is merged with the remote metadata by deployMavenMetadata
+			if isSnapshot && (fileName == mavenMetadataFile || strings.TrimSuffix(fileName, path.Ext(fileName)) == mavenMetadataFile) {
+				continue
+			}
+
+			var fileWithChecksums []mavenDeployFile
+			fileWithChecksums, err = planArtifactFile(fileSystem, mavenRepository, localRepository, path.Join(artifactVersionPath, fileName), localFileNames)
+			if err != nil {
+				break
+			}
+			files = append(files, fileWithChecksums...)
+		}
+	}
+
+	return files, err
+}
+
+func planArtifactFile(
+	fileSystem utils.FileSystem,
+	mavenRepository string,
+	localRepository string,
+	artifactFilePath string,
+	localFileNames map[string]bool) ([]mavenDeployFile, error) {
+
+	var files []mavenDeployFile
+	fileName := path.Base(artifactFilePath)
+
+	checksums, size, err := calculateMavenChecksums(fileSystem, artifactFilePath)
+	if err == nil {
+		file := mavenDeployFile{LocalPath: artifactFilePath, Size: size, Checksums: checksums}
+		file.Url, err = getMavenDeployUrl(mavenRepository, localRepository, artifactFilePath)
+		files = append(files, file)
+	}
+
+	for _, algorithm := range mavenChecksumAlgorithms {
+		if err != nil {
+			break
+		}
+
+		checksumFile := mavenDeployFile{}
+		checksumFilePath := artifactFilePath + "." + algorithm.Extension
+
+		if localFileNames[fileName+"."+algorithm.Extension] {
+			var contents string
+			contents, err = verifyMavenChecksumFile(fileSystem, checksumFilePath, checksums[algorithm.Extension])
+			if err == nil {
+				checksumFile.LocalPath = checksumFilePath
+				checksumFile.Size = int64(len(contents))
+			}
+		} else {
+			checksumFile.contents = []byte(checksums[algorithm.Extension])
+			checksumFile.Size = int64(len(checksumFile.contents))
+		}
+
+		if err == nil {
+			checksumFile.Url, err = getMavenDeployUrl(mavenRepository, localRepository, checksumFilePath)
+			files = append(files, checksumFile)
+		}
+	}
+
+	return files, err
+}
+
+// Works out the urls of the maven-metadata.xml files which deployMavenMetadata merges for a version of an artifact.
+func planMergedMetadataUrls(
+	fileSystem utils.FileSystem,
+	mavenRepository string,
+	localRepository string,
+	artifact localMavenArtifact) ([]string, error) {
+
+	var err error
+	var metadataPaths []string
+
+	if isMavenSnapshotVersion(artifact.Version) {
+		var isLocalMetadata bool
+		localMetadataPath := path.Join(artifact.VersionPath, mavenMetadataFile)
+		isLocalMetadata, err = fileSystem.Exists(localMetadataPath)
+		if isLocalMetadata {
+			metadataPaths = append(metadataPaths, localMetadataPath)
+		}
+	}
+	metadataPaths = append(metadataPaths, path.Join(path.Dir(artifact.VersionPath), mavenMetadataFile))
+
+	urls := make([]string, 0, len(metadataPaths))
+	for _, metadataPath := range metadataPaths {
+		if err != nil {
+			break
+		}
+
+		var metadataUrl string
+		metadataUrl, err = getMavenDeployUrl(mavenRepository, localRepository, metadataPath)
+		urls = append(urls, metadataUrl)
+	}
+
+	return urls, err
+}
+
+// Finds the same artifacts as a deploy would, and works out every file which would be uploaded, without
+// uploading anything. The plan is printed, or written as json to the plan file if there is one.
+func mavenDeployDryRun(
+	fileSystem utils.FileSystem,
+	mavenRepositoryUrl string,
+	mavenDeployDirectory string,
+	selection mavenArtifactSelection,
+	planFilePath string,
+	writer io.Writer) error {
+
+	var artifacts []localMavenArtifact
+	plan := mavenDeployPlan{Repository: mavenRepositoryUrl, Artifacts: make([]mavenDeployPlanArtifact, 0)}
+
+	err := selection.validate()
+	if err == nil {
+		artifacts, err = findLocalMavenArtifacts(fileSystem, mavenDeployDirectory, selection)
+	}
+
+	for _, artifact := range artifacts {
+		if err != nil {
+			break
+		}
+
+		planArtifact := mavenDeployPlanArtifact{
+			GroupId:    artifact.GroupId,
+			ArtifactId: artifact.ArtifactId,
+			Version:    artifact.Version,
+		}
+
+		planArtifact.Files, err = planArtifactFiles(fileSystem, mavenRepositoryUrl, mavenDeployDirectory, artifact.VersionPath)
+		if err == nil {
+			planArtifact.MergedMetadataUrls, err = planMergedMetadataUrls(fileSystem, mavenRepositoryUrl, mavenDeployDirectory, artifact)
+		}
+
+		for _, file := range planArtifact.Files {
+			plan.FileCount++
+			plan.TotalSize += file.Size
+		}
+		plan.Artifacts = append(plan.Artifacts, planArtifact)
+	}
+
+	if err == nil {
+		if planFilePath != "" {
+			var planJson []byte
+			planJson, err = json.MarshalIndent(plan, "", "  ")
+			if err == nil {
+				err = fileSystem.WriteTextFile(planFilePath, string(planJson)+"\n")
+			}
+			if err == nil {
+				fmt.Fprintf(writer, "Dry run complete - deploy plan written to %v\n", planFilePath)
+			}
+		} else {
+			printMavenDeployPlan(writer, plan)
+		}
+	}
+
+	return err
+}
+
+func printMavenDeployPlan(writer io.Writer, plan mavenDeployPlan) {
+	fmt.Fprintf(writer, "Dry run - nothing will be uploaded to %v\n", plan.Repository)
+
+	for _, artifact := range plan.Artifacts {
+		fmt.Fprintf(writer, "%v:%v:%v\n", artifact.GroupId, artifact.ArtifactId, artifact.Version)
+
+		for _, file := range artifact.Files {
+			if file.Checksums != nil {
+				fmt.Fprintf(writer, "    %v (%v bytes, sha1 %v)\n", file.Url, file.Size, file.Checksums["sha1"])
+			} else if file.LocalPath == "" {
+				fmt.Fprintf(writer, "    %v (%v bytes, generated)\n", file.Url, file.Size)
+			} else {
+				fmt.Fprintf(writer, "    %v (%v bytes)\n", file.Url, file.Size)
+			}
+		}
+
+		for _, metadataUrl := range artifact.MergedMetadataUrls {
+			fmt.Fprintf(writer, "    %v (merged with the remote metadata, with checksum files)\n", metadataUrl)
+		}
+	}
+
+	fmt.Fprintf(writer, "Dry run complete - artifacts: %v, files: %v, bytes: %v\n", len(plan.Artifacts), plan.FileCount, plan.TotalSize)
+}
@@ -0,0 +1,211 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// A remote repository which fails the test if a dry run sends it anything.
+func createNoRequestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Fail(t, "A dry run should not send any requests", "%v %v", req.Method, req.URL.Path)
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+}
+
+func TestDryRunPrintsPlanWithoutUploading(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifacts(mockFileSystem, 1, "localRepository/test/artifact/group")
+
+	mockServer := createNoRequestServer(t)
+	defer mockServer.Close()
+
+	var output bytes.Buffer
+
+	// When...
+	err := mavenDeployDryRun(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "", &output)
+
+	// Then...
+	assert.Nil(t, err, "Failed to plan the deploy")
+
+	artifactUrl := mockServer.URL + "/test/artifact/group/artifact-1/0.27.0/pom.xml"
+	assert.Contains(t, output.String(), "test.artifact.group:artifact-1:0.27.0\n")
+	assert.Contains(t, output.String(), artifactUrl+" (13 bytes, sha1 f41e9246d434284669329bf73690dd639a473d55)\n")
+	assert.Contains(t, output.String(), artifactUrl+".md5 (32 bytes, generated)\n")
+	assert.Contains(t, output.String(), mockServer.URL+"/test/artifact/group/artifact-1/maven-metadata.xml (merged with the remote metadata")
+
+	// The pom, then the md5, sha1, sha256 and sha512 checksums
+	assert.Contains(t, output.String(), "Dry run complete - artifacts: 1, files: 5, bytes: 277\n")
+}
+
+func TestDryRunWritesPlanFileAsJson(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifacts(mockFileSystem, 1, "localRepository/test/artifact/group")
+	localSha1Path := "localRepository/test/artifact/group/artifact-1/0.27.0/pom.xml.sha1"
+	mockFileSystem.WriteTextFile(localSha1Path, "f41e9246d434284669329bf73690dd639a473d55  pom.xml\n")
+
+	mockServer := createNoRequestServer(t)
+	defer mockServer.Close()
+
+	var output bytes.Buffer
+
+	// When...
+	err := mavenDeployDryRun(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "plan.json", &output)
+
+	// Then...
+	assert.Nil(t, err, "Failed to plan the deploy")
+	assert.Equal(t, "Dry run complete - deploy plan written to plan.json\n", output.String())
+
+	contents, err := mockFileSystem.ReadTextFile("plan.json")
+	assert.Nil(t, err)
+
+	var plan mavenDeployPlan
+	err = json.Unmarshal([]byte(contents), &plan)
+	assert.Nil(t, err, "The plan file should be json")
+
+	assert.Equal(t, mockServer.URL, plan.Repository)
+	assert.Equal(t, 5, plan.FileCount)
+	assert.Len(t, plan.Artifacts, 1)
+
+	artifact := plan.Artifacts[0]
+	assert.Equal(t, "test.artifact.group", artifact.GroupId)
+	assert.Equal(t, "artifact-1", artifact.ArtifactId)
+	assert.Equal(t, "0.27.0", artifact.Version)
+	assert.Equal(t, []string{mockServer.URL + "/test/artifact/group/artifact-1/maven-metadata.xml"}, artifact.MergedMetadataUrls)
+
+	filesByUrl := make(map[string]mavenDeployFile)
+	for _, file := range artifact.Files {
+		filesByUrl[file.Url] = file
+	}
+
+	artifactUrl := mockServer.URL + "/test/artifact/group/artifact-1/0.27.0/pom.xml"
+	pom := filesByUrl[artifactUrl]
+	assert.Equal(t, int64(13), pom.Size)
+	assert.Equal(t, "bf82d5884c7bd1a86a8ed8648bc2c9e8", pom.Checksums["md5"])
+	assert.Equal(t, "f41e9246d434284669329bf73690dd639a473d55", pom.Checksums["sha1"])
+
+	// The local checksum file would be deployed as it is, and the others generated
+	assert.Equal(t, localSha1Path, filesByUrl[artifactUrl+".sha1"].LocalPath)
+	assert.Equal(t, int64(50), filesByUrl[artifactUrl+".sha1"].Size)
+	assert.Equal(t, "", filesByUrl[artifactUrl+".md5"].LocalPath)
+	assert.Equal(t, int64(32), filesByUrl[artifactUrl+".md5"].Size)
+}
+
+func TestDryRunReadsEachLocalChecksumFileOnce(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewOverridableMockFileSystem()
+	createLocalArtifacts(mockFileSystem, 1, "localRepository/test/artifact/group")
+	localSha1Path := "localRepository/test/artifact/group/artifact-1/0.27.0/pom.xml.sha1"
+	mockFileSystem.WriteTextFile(localSha1Path, "f41e9246d434284669329bf73690dd639a473d55")
+
+	readCounts := make(map[string]int)
+	readTextFile := mockFileSystem.VirtualFunction_ReadTextFile
+	mockFileSystem.VirtualFunction_ReadTextFile = func(filePath string) (string, error) {
+		readCounts[filePath]++
+		return readTextFile(filePath)
+	}
+
+	var output bytes.Buffer
+
+	// When...
+	err := mavenDeployDryRun(mockFileSystem, "http://localhost", "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "", &output)
+
+	// Then...
+	assert.Nil(t, err, "Failed to plan the deploy")
+	assert.Equal(t, 1, readCounts[localSha1Path])
+	assert.Contains(t, output.String(), "pom.xml.sha1 (40 bytes)\n")
+}
+
+func TestDryRunFailsIfLocalChecksumFileDoesNotMatch(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifacts(mockFileSystem, 1, "localRepository/test/artifact/group")
+	mockFileSystem.WriteTextFile("localRepository/test/artifact/group/artifact-1/0.27.0/pom.xml.md5", "0123456789abcdef0123456789abcdef")
+
+	mockServer := createNoRequestServer(t)
+	defer mockServer.Close()
+
+	var output bytes.Buffer
+
+	// When...
+	err := mavenDeployDryRun(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "", &output)
+
+	// Then...
+	assert.NotNil(t, err, "A dry run should find the checksum files which would stop the deploy")
+	assert.Contains(t, err.Error(), "pom.xml.md5 does not match")
+}
+
+func TestDryRunPlansTheSameUploadsAsDeploy(t *testing.T) {
+
+	// Given...
+	selection := mavenArtifactSelection{
+		Groups:   []string{"dev.galasa.managers.*", "org.example"},
+		Version:  "0.36.*",
+		Excludes: []string{"*cloud*"},
+	}
+
+	var lock sync.Mutex
+	putUrls := make(map[string]bool)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
+
+		lock.Lock()
+		putUrls["http://"+req.Host+req.URL.Path] = true
+		lock.Unlock()
+		writer.WriteHeader(http.StatusCreated)
+	}))
+
+	defer mockServer.Close()
+
+	err := mavenDeploy(createLocalRepository(), mockServer.URL, "localRepository", selection, "test", createTestUploadOptions())
+	assert.Nil(t, err, "Failed to deploy artifacts")
+
+	mockFileSystem := createLocalRepository()
+	var output bytes.Buffer
+
+	// When...
+	err = mavenDeployDryRun(mockFileSystem, mockServer.URL, "localRepository", selection, "plan.json", &output)
+
+	// Then...
+	assert.Nil(t, err, "Failed to plan the deploy")
+
+	contents, _ := mockFileSystem.ReadTextFile("plan.json")
+	var plan mavenDeployPlan
+	err = json.Unmarshal([]byte(contents), &plan)
+	assert.Nil(t, err)
+
+	plannedUrls := make(map[string]bool)
+	for _, artifact := range plan.Artifacts {
+		for _, file := range artifact.Files {
+			plannedUrls[file.Url] = true
+		}
+		for _, metadataUrl := range artifact.MergedMetadataUrls {
+			plannedUrls[metadataUrl] = true
+			for _, algorithm := range mavenChecksumAlgorithms {
+				plannedUrls[metadataUrl+"."+algorithm.Extension] = true
+			}
+		}
+	}
+
+	assert.Equal(t, putUrls, plannedUrls)
+}
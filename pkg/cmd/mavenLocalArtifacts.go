@@ -0,0 +1,234 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+// Which artifacts in a local maven repository are to be deployed. The patterns are globs,
+// where * matches any characters, ? matches one character and [a-z] matches a range.
+type mavenArtifactSelection struct {
+	// Patterns which the groupId must match, eg. dev.galasa or dev.galasa.managers.*
+	// The subgroups of a matching group are included too.
+	Groups []string
+	// A pattern which the version must match, or "" for every version.
+	Version string
+	// Patterns which the artifactId must match, or none for every artifact.
+	Includes []string
+	// Patterns which the artifactId must not match.
+	Excludes []string
+}
+
+// A version of an artifact in the local maven repository.
+type localMavenArtifact struct {
+	GroupId    string
+	ArtifactId string
+	Version    string
+	// The folder holding the files of the version.
+	VersionPath string
+}
+
+// Checks that every pattern of the selection is a valid glob.
+func (selection mavenArtifactSelection) validate() error {
+	var err error
+
+	patterns := append(append(append([]string{}, selection.Groups...), selection.Includes...), selection.Excludes...)
+	patterns = append(patterns, selection.Version)
+
+	if len(selection.Groups) == 0 {
+		err = fmt.Errorf("no groups to deploy have been provided, use --group")
+	}
+
+	for _, pattern := range patterns {
+		if err != nil {
+			break
+		}
+		if _, matchErr := path.Match(pattern, ""); matchErr != nil {
+			err = fmt.Errorf("'%v' is not a valid pattern - %v", pattern, matchErr.Error())
+		}
+	}
+
+	return err
+}
+
+// Returns true if the group, or a group it is a subgroup of, matches one of the group patterns.
+func (selection mavenArtifactSelection) isGroupSelected(groupId string) bool {
+	isSelected := false
+
+	// Dots are swapped for slashes so that a * only matches within one part of the groupId.
+	groupPath := strings.ReplaceAll(groupId, ".", "/")
+	for _, pattern := range selection.Groups {
+		patternPath := strings.ReplaceAll(pattern, ".", "/")
+		for candidate := groupPath; candidate != "." && candidate != "/" && !isSelected; candidate = path.Dir(candidate) {
+			isSelected, _ = path.Match(patternPath, candidate)
+		}
+	}
+
+	return isSelected
+}
+
+func (selection mavenArtifactSelection) isArtifactSelected(artifactId string) bool {
+	isSelected := len(selection.Includes) == 0
+	for _, pattern := range selection.Includes {
+		if isMatch, _ := path.Match(pattern, artifactId); isMatch {
+			isSelected = true
+			break
+		}
+	}
+
+	for _, pattern := range selection.Excludes {
+		if isMatch, _ := path.Match(pattern, artifactId); isMatch {
+			isSelected = false
+			break
+		}
+	}
+
+	return isSelected
+}
+
+func (selection mavenArtifactSelection) isVersionSelected(version string) bool {
+	isSelected := true
+	if selection.Version != "" {
+		isSelected, _ = path.Match(selection.Version, version)
+	}
+	return isSelected
+}
+
+// Finds the artifact versions in the local repository which the selection picks, sorted by groupId,
+// artifactId and version.
+//
+// An artifact is a folder holding a maven-metadata.xml file, and its versions are the folders inside it.
+// The groupId of the artifact is the path of its parent folder in the local repository.
+func findLocalMavenArtifacts(fileSystem utils.FileSystem, localRepository string, selection mavenArtifactSelection) ([]localMavenArtifact, error) {
+	var err error
+	artifactsByVersionPath := make(map[string]localMavenArtifact)
+
+	localRepository = path.Clean(localRepository)
+
+	for _, groupPattern := range selection.Groups {
+		if err != nil {
+			break
+		}
+
+		var isBaseDirectory bool
+		baseDirectory := path.Join(localRepository, getMavenGroupPatternBase(groupPattern))
+		isBaseDirectory, err = fileSystem.DirExists(baseDirectory)
+		if err != nil || !isBaseDirectory {
+			log.Printf("findLocalMavenArtifacts - no folder %v for group %v", baseDirectory, groupPattern)
+			continue
+		}
+
+		var artifactDirectories []string
+		artifactDirectories, err = findMavenArtifactDirectories(fileSystem, baseDirectory)
+
+		for _, artifactDirectory := range artifactDirectories {
+			if err != nil {
+				break
+			}
+
+			relativePath := strings.TrimPrefix(artifactDirectory, localRepository+"/")
+			groupId := strings.ReplaceAll(path.Dir(relativePath), "/", ".")
+			artifactId := path.Base(relativePath)
+
+			if groupId == "." || !selection.isGroupSelected(groupId) || !selection.isArtifactSelected(artifactId) {
+				continue
+			}
+
+			var versionPaths []string
+			versionPaths, err = findMavenVersionDirectories(fileSystem, artifactDirectory)
+			for _, versionPath := range versionPaths {
+				version := path.Base(versionPath)
+				if selection.isVersionSelected(version) {
+					artifactsByVersionPath[versionPath] = localMavenArtifact{
+						GroupId:     groupId,
+						ArtifactId:  artifactId,
+						Version:     version,
+						VersionPath: versionPath,
+					}
+				}
+			}
+		}
+	}
+
+	artifacts := make([]localMavenArtifact, 0, len(artifactsByVersionPath))
+	for _, artifact := range artifactsByVersionPath {
+		artifacts = append(artifacts, artifact)
+	}
+	sort.Slice(artifacts, func(i, j int) bool {
+		if artifacts[i].GroupId != artifacts[j].GroupId {
+			return artifacts[i].GroupId < artifacts[j].GroupId
+		}
+		if artifacts[i].ArtifactId != artifacts[j].ArtifactId {
+			return artifacts[i].ArtifactId < artifacts[j].ArtifactId
+		}
+		// Versions are uploaded in this order, so the highest ends up as the latest in the remote metadata.
+		return utils.CompareVersions(artifacts[i].Version, artifacts[j].Version) < 0
+	})
+
+	return artifacts, err
+}
+
+// Returns the folder path of the part of a group pattern before any wildcards, which is where
+// the artifacts matching the pattern must be. eg. dev.galasa.managers.* gives dev/galasa/managers
+func getMavenGroupPatternBase(groupPattern string) string {
+	var baseParts []string
+	for _, part := range strings.Split(groupPattern, ".") {
+		if strings.ContainsAny(part, "*?[\\") {
+			break
+		}
+		baseParts = append(baseParts, part)
+	}
+	return strings.Join(baseParts, "/")
+}
+
+// Returns every folder under the base folder which holds a maven-metadata.xml file.
+func findMavenArtifactDirectories(fileSystem utils.FileSystem, baseDirectory string) ([]string, error) {
+	var artifactDirectories []string
+
+	err := fileSystem.WalkDir(baseDirectory, func(filePath string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			// Carry on with the rest of the repository
+			log.Printf("findMavenArtifactDirectories - unable to read %v - %v", filePath, walkErr.Error())
+		} else if path.Base(filePath) == mavenMetadataFile {
+			isDirectory, err := fileSystem.DirExists(filePath)
+			if err == nil && !isDirectory {
+				artifactDirectories = append(artifactDirectories, path.Dir(filePath))
+			}
+		}
+		return nil
+	})
+
+	return artifactDirectories, err
+}
+
+// Returns the folders directly inside an artifact's folder, which are its versions.
+func findMavenVersionDirectories(fileSystem utils.FileSystem, artifactDirectory string) ([]string, error) {
+	var versionPaths []string
+
+	entries, err := fileSystem.ReadDir(artifactDirectory)
+	for _, entry := range entries {
+		if err != nil {
+			break
+		}
+
+		var isDirectory bool
+		versionPath := path.Join(artifactDirectory, entry.Name())
+		isDirectory, err = fileSystem.DirExists(versionPath)
+		if isDirectory {
+			versionPaths = append(versionPaths, versionPath)
+		}
+	}
+
+	return versionPaths, err
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createLocalRepository() utils.FileSystem {
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifact(mockFileSystem, "localRepository/dev/galasa/dev.galasa.framework", "0.36.0", "0.37.0")
+	createLocalArtifact(mockFileSystem, "localRepository/dev/galasa/dev.galasa.api", "0.36.0")
+	createLocalArtifact(mockFileSystem, "localRepository/dev/galasa/managers/zos/dev.galasa.zos.manager", "0.36.0")
+	createLocalArtifact(mockFileSystem, "localRepository/dev/galasa/managers/cloud/dev.galasa.cloud.manager", "0.36.0")
+	createLocalArtifact(mockFileSystem, "localRepository/dev/galasaextra/dev.galasaextra.tool", "1.0.0")
+	createLocalArtifact(mockFileSystem, "localRepository/org/example/example.lib", "0.36.0")
+	return mockFileSystem
+}
+
+func createLocalArtifact(mockFileSystem utils.FileSystem, artifactPath string, versions ...string) {
+	mockFileSystem.MkdirAll(artifactPath)
+	mockFileSystem.WriteTextFile(artifactPath+"/maven-metadata.xml", "dummy maven-metadata.xml")
+	for _, version := range versions {
+		mockFileSystem.MkdirAll(artifactPath + "/" + version)
+		mockFileSystem.WriteTextFile(artifactPath+"/"+version+"/pom.xml", "dummy pom.xml")
+	}
+}
+
+func findTestArtifacts(t *testing.T, selection mavenArtifactSelection) []string {
+	artifacts, err := findLocalMavenArtifacts(createLocalRepository(), "localRepository", selection)
+	assert.Nil(t, err)
+
+	var gavs []string
+	for _, artifact := range artifacts {
+		gavs = append(gavs, artifact.GroupId+":"+artifact.ArtifactId+":"+artifact.Version)
+	}
+	return gavs
+}
+
+func TestFindArtifactsIncludesSubgroups(t *testing.T) {
+	gavs := findTestArtifacts(t, mavenArtifactSelection{Groups: []string{"dev.galasa"}, Version: "0.36.0"})
+
+	assert.Equal(t, []string{
+		"dev.galasa:dev.galasa.api:0.36.0",
+		"dev.galasa:dev.galasa.framework:0.36.0",
+		"dev.galasa.managers.cloud:dev.galasa.cloud.manager:0.36.0",
+		"dev.galasa.managers.zos:dev.galasa.zos.manager:0.36.0",
+	}, gavs)
+}
+
+func TestFindArtifactsWithGroupPattern(t *testing.T) {
+	gavs := findTestArtifacts(t, mavenArtifactSelection{Groups: []string{"dev.galasa.managers.*"}})
+
+	assert.Equal(t, []string{
+		"dev.galasa.managers.cloud:dev.galasa.cloud.manager:0.36.0",
+		"dev.galasa.managers.zos:dev.galasa.zos.manager:0.36.0",
+	}, gavs)
+}
+
+func TestFindArtifactsWithPrefixGroupPattern(t *testing.T) {
+	gavs := findTestArtifacts(t, mavenArtifactSelection{Groups: []string{"dev.galasa*"}, Version: "1.0.0"})
+
+	assert.Equal(t, []string{"dev.galasaextra:dev.galasaextra.tool:1.0.0"}, gavs)
+}
+
+func TestFindArtifactsWithSeveralGroups(t *testing.T) {
+	gavs := findTestArtifacts(t, mavenArtifactSelection{
+		Groups:  []string{"dev.galasa.managers.zos", "org.example", "dev.galasa.managers"},
+		Version: "0.36.0",
+	})
+
+	// Artifacts matching more than one group are only found once
+	assert.Equal(t, []string{
+		"dev.galasa.managers.cloud:dev.galasa.cloud.manager:0.36.0",
+		"dev.galasa.managers.zos:dev.galasa.zos.manager:0.36.0",
+		"org.example:example.lib:0.36.0",
+	}, gavs)
+}
+
+func TestFindArtifactsWithVersionPattern(t *testing.T) {
+	gavs := findTestArtifacts(t, mavenArtifactSelection{Groups: []string{"dev.galasa"}, Version: "0.3[7-9].*", Includes: []string{"*framework"}})
+
+	assert.Equal(t, []string{"dev.galasa:dev.galasa.framework:0.37.0"}, gavs)
+}
+
+func TestFindArtifactsWithoutVersionFindsEveryVersion(t *testing.T) {
+	gavs := findTestArtifacts(t, mavenArtifactSelection{Groups: []string{"dev.galasa"}, Includes: []string{"dev.galasa.framework"}})
+
+	assert.Equal(t, []string{"dev.galasa:dev.galasa.framework:0.36.0", "dev.galasa:dev.galasa.framework:0.37.0"}, gavs)
+}
+
+func TestFindArtifactsOrdersVersionsNumerically(t *testing.T) {
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifact(mockFileSystem, "localRepository/dev/galasa/dev.galasa.framework", "0.10.0", "0.9.0")
+
+	artifacts, err := findLocalMavenArtifacts(mockFileSystem, "localRepository", mavenArtifactSelection{Groups: []string{"dev.galasa"}})
+	assert.Nil(t, err)
+
+	assert.Len(t, artifacts, 2)
+	assert.Equal(t, "0.9.0", artifacts[0].Version)
+	assert.Equal(t, "0.10.0", artifacts[1].Version)
+}
+
+func TestFindArtifactsWithIncludesAndExcludes(t *testing.T) {
+	gavs := findTestArtifacts(t, mavenArtifactSelection{
+		Groups:   []string{"dev.galasa"},
+		Version:  "0.36.0",
+		Includes: []string{"*.manager", "dev.galasa.api"},
+		Excludes: []string{"dev.galasa.cloud.*"},
+	})
+
+	assert.Equal(t, []string{
+		"dev.galasa:dev.galasa.api:0.36.0",
+		"dev.galasa.managers.zos:dev.galasa.zos.manager:0.36.0",
+	}, gavs)
+}
+
+func TestFindArtifactsInMissingGroupFindsNothing(t *testing.T) {
+	gavs := findTestArtifacts(t, mavenArtifactSelection{Groups: []string{"com.missing"}})
+
+	assert.Empty(t, gavs)
+}
+
+func TestSelectionWithBadPatternIsAnError(t *testing.T) {
+	err := mavenArtifactSelection{Groups: []string{"dev.galasa"}, Excludes: []string{"[abc"}}.validate()
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "'[abc' is not a valid pattern")
+}
+
+func TestSelectionWithoutGroupIsAnError(t *testing.T) {
+	err := mavenArtifactSelection{Version: "0.36.0"}.validate()
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "--group")
+}
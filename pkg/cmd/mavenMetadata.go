@@ -0,0 +1,307 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+const (
+	mavenMetadataFile = "maven-metadata.xml"
+
+	// The format maven uses for the lastUpdated of a maven-metadata.xml file
+	mavenMetadataTimeFormat = "20060102150405"
+)
+
+// A maven-metadata.xml file. The metadata of an artifact lists the versions of the artifact. For SNAPSHOT
+// versions, the version folder has metadata too, listing the timestamped files of the latest snapshot.
+type mavenMetadata struct {
+	XMLName      xml.Name                 `xml:"metadata"`
+	ModelVersion string                   `xml:"modelVersion,attr,omitempty"`
+	GroupId      string                   `xml:"groupId,omitempty"`
+	ArtifactId   string                   `xml:"artifactId,omitempty"`
+	Version      string                   `xml:"version,omitempty"`
+	Versioning   *mavenMetadataVersioning `xml:"versioning,omitempty"`
+}
+
+type mavenMetadataVersioning struct {
+	Latest           string                         `xml:"latest,omitempty"`
+	Release          string                         `xml:"release,omitempty"`
+	Snapshot         *mavenMetadataSnapshot         `xml:"snapshot,omitempty"`
+	Versions         *mavenMetadataVersions         `xml:"versions,omitempty"`
+	LastUpdated      string                         `xml:"lastUpdated,omitempty"`
+	SnapshotVersions *mavenMetadataSnapshotVersions `xml:"snapshotVersions,omitempty"`
+}
+
+// The lists are held in their own structs so that they are left out of the xml when there are none.
+type mavenMetadataVersions struct {
+	Version []string `xml:"version"`
+}
+
+type mavenMetadataSnapshotVersions struct {
+	SnapshotVersion []mavenMetadataSnapshotVersion `xml:"snapshotVersion"`
+}
+
+type mavenMetadataSnapshot struct {
+	Timestamp   string `xml:"timestamp,omitempty"`
+	BuildNumber int    `xml:"buildNumber,omitempty"`
+}
+
+// One of the timestamped files of a SNAPSHOT version, eg. the jar or the pom.
+type mavenMetadataSnapshotVersion struct {
+	Classifier string `xml:"classifier,omitempty"`
+	Extension  string `xml:"extension"`
+	Value      string `xml:"value"`
+	Updated    string `xml:"updated"`
+}
+
+func parseMavenMetadata(contents []byte) (mavenMetadata, error) {
+	var metadata mavenMetadata
+	err := xml.Unmarshal(contents, &metadata)
+	return metadata, err
+}
+
+func (metadata mavenMetadata) toXml() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+
+	contents, err := xml.MarshalIndent(metadata, "", "  ")
+	if err == nil {
+		buffer.Write(contents)
+		buffer.WriteString("\n")
+	}
+	return buffer.Bytes(), err
+}
+
+func isMavenSnapshotVersion(version string) bool {
+	return strings.HasSuffix(version, "-SNAPSHOT")
+}
+
+// Adds a version which has been deployed to the metadata of an artifact from the remote repository, keeping
+// the versions which were deployed before. latest and release are moved on if the version is newer.
+func mergeMavenArtifactMetadata(remote mavenMetadata, groupId string, artifactId string, version string, lastUpdated string) mavenMetadata {
+	merged := remote
+	if merged.GroupId == "" {
+		merged.GroupId = groupId
+	}
+	if merged.ArtifactId == "" {
+		merged.ArtifactId = artifactId
+	}
+
+	versioning := mavenMetadataVersioning{}
+	if remote.Versioning != nil {
+		versioning = *remote.Versioning
+	}
+
+	var versions []string
+	if versioning.Versions != nil {
+		versions = versioning.Versions.Version
+	}
+
+	isListed := false
+	for _, listedVersion := range versions {
+		if listedVersion == version {
+			isListed = true
+			break
+		}
+	}
+	if !isListed {
+		versioning.Versions = &mavenMetadataVersions{Version: append(append([]string{}, versions...), version)}
+	}
+
+	if versioning.Latest == "" || compareMavenVersions(version, versioning.Latest) > 0 {
+		versioning.Latest = version
+	}
+	if !isMavenSnapshotVersion(version) && (versioning.Release == "" || compareMavenVersions(version, versioning.Release) > 0) {
+		versioning.Release = version
+	}
+	versioning.LastUpdated = lastUpdated
+
+	merged.Versioning = &versioning
+	return merged
+}
+
+func compareMavenVersions(version1 string, version2 string) int {
+	return utils.CompareVersions(version1, version2)
+}
+
+// Merges the metadata of a SNAPSHOT version folder which has been deployed with the metadata of the same
+// folder in the remote repository. The remote metadata lists the timestamped files of earlier snapshots,
+// so for each kind of file, the one which was updated most recently is kept, as is the newest snapshot.
+func mergeMavenSnapshotMetadata(remote mavenMetadata, local mavenMetadata, lastUpdated string) mavenMetadata {
+	merged := local
+
+	versioning := mavenMetadataVersioning{}
+	if local.Versioning != nil {
+		versioning = *local.Versioning
+	}
+
+	if remote.Versioning != nil {
+		remoteSnapshot := remote.Versioning.Snapshot
+		if remoteSnapshot != nil && (versioning.Snapshot == nil || remoteSnapshot.Timestamp > versioning.Snapshot.Timestamp) {
+			versioning.Snapshot = remoteSnapshot
+		}
+
+		var snapshotVersions []mavenMetadataSnapshotVersion
+		if remote.Versioning.SnapshotVersions != nil {
+			snapshotVersions = append(snapshotVersions, remote.Versioning.SnapshotVersions.SnapshotVersion...)
+		}
+
+		var localVersions []mavenMetadataSnapshotVersion
+		if versioning.SnapshotVersions != nil {
+			localVersions = versioning.SnapshotVersions.SnapshotVersion
+		}
+
+		for _, localVersion := range localVersions {
+			isReplaced := false
+			for index, remoteVersion := range snapshotVersions {
+				if remoteVersion.Classifier == localVersion.Classifier && remoteVersion.Extension == localVersion.Extension {
+					if localVersion.Updated >= remoteVersion.Updated {
+						snapshotVersions[index] = localVersion
+					}
+					isReplaced = true
+					break
+				}
+			}
+			if !isReplaced {
+				snapshotVersions = append(snapshotVersions, localVersion)
+			}
+		}
+		if len(snapshotVersions) > 0 {
+			versioning.SnapshotVersions = &mavenMetadataSnapshotVersions{SnapshotVersion: snapshotVersions}
+		}
+	}
+
+	versioning.LastUpdated = lastUpdated
+	merged.Versioning = &versioning
+	return merged
+}
+
+// Merges the version which has been deployed into the maven-metadata.xml files of the remote repository, so that
+// the versions deployed before are not lost. For a SNAPSHOT version, the metadata of the version folder is merged too.
+// The merged metadata is uploaded with its checksum files.
+func deployMavenMetadata(
+	fileSystem utils.FileSystem,
+	uploader *mavenUploader,
+	mavenRepository string,
+	localRepository string,
+	artifact localMavenArtifact) error {
+
+	var err error
+	lastUpdated := time.Now().UTC().Format(mavenMetadataTimeFormat)
+	artifactDirectory := path.Dir(artifact.VersionPath)
+
+	if isMavenSnapshotVersion(artifact.Version) {
+		localMetadataPath := path.Join(artifact.VersionPath, mavenMetadataFile)
+
+		var isLocalMetadata bool
+		isLocalMetadata, err = fileSystem.Exists(localMetadataPath)
+		if err == nil && isLocalMetadata {
+			err = deployMavenSnapshotMetadata(fileSystem, uploader, mavenRepository, localRepository, localMetadataPath, lastUpdated)
+		}
+	}
+
+	if err == nil {
+		var metadataUrl string
+		metadataUrl, err = getMavenDeployUrl(mavenRepository, localRepository, path.Join(artifactDirectory, mavenMetadataFile))
+		if err == nil {
+			var remote mavenMetadata
+			remote, err = getRemoteMavenMetadata(uploader, metadataUrl)
+			if err == nil {
+				merged := mergeMavenArtifactMetadata(remote, artifact.GroupId, artifact.ArtifactId, artifact.Version, lastUpdated)
+				err = putMavenMetadata(uploader, metadataUrl, merged)
+			}
+		}
+	}
+
+	return err
+}
+
+func deployMavenSnapshotMetadata(
+	fileSystem utils.FileSystem,
+	uploader *mavenUploader,
+	mavenRepository string,
+	localRepository string,
+	localMetadataPath string,
+	lastUpdated string) error {
+
+	var local mavenMetadata
+	var remote mavenMetadata
+
+	contents, err := fileSystem.ReadTextFile(localMetadataPath)
+	if err == nil {
+		local, err = parseMavenMetadata([]byte(contents))
+	}
+	if err != nil {
+		err = fmt.Errorf("unable to read the snapshot metadata %v - %v", localMetadataPath, err.Error())
+	}
+
+	var metadataUrl string
+	if err == nil {
+		metadataUrl, err = getMavenDeployUrl(mavenRepository, localRepository, localMetadataPath)
+	}
+	if err == nil {
+		remote, err = getRemoteMavenMetadata(uploader, metadataUrl)
+	}
+	if err == nil {
+		err = putMavenMetadata(uploader, metadataUrl, mergeMavenSnapshotMetadata(remote, local, lastUpdated))
+	}
+
+	return err
+}
+
+// Fetches a maven-metadata.xml file from the remote repository. Empty metadata is returned if it isn't there yet.
+func getRemoteMavenMetadata(uploader *mavenUploader, metadataUrl string) (mavenMetadata, error) {
+	var metadata mavenMetadata
+
+	contents, isFound, err := uploader.get(metadataUrl)
+	if err == nil && isFound {
+		metadata, err = parseMavenMetadata(contents)
+		if err != nil {
+			err = fmt.Errorf("unable to parse the remote metadata %v - %v", metadataUrl, err.Error())
+		}
+	}
+
+	return metadata, err
+}
+
+// Uploads a maven-metadata.xml file, followed by its checksum files. They are uploaded every time, as the
+// metadata is merged again for each version deployed, including when a deploy is resumed.
+func putMavenMetadata(uploader *mavenUploader, metadataUrl string, metadata mavenMetadata) error {
+	contents, err := metadata.toXml()
+	if err == nil {
+		log.Printf("putMavenMetadata - merged metadata for %v\n%v", metadataUrl, string(contents))
+		err = uploader.putAlways(metadataUrl, func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(contents)), nil
+		})
+	}
+
+	for _, algorithm := range mavenChecksumAlgorithms {
+		if err != nil {
+			break
+		}
+
+		hash := algorithm.NewHash()
+		hash.Write(contents)
+		checksum := hex.EncodeToString(hash.Sum(nil))
+
+		err = uploader.putAlways(metadataUrl+"."+algorithm.Extension, func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(checksum)), nil
+		})
+	}
+
+	return err
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const remoteArtifactMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata modelVersion="1.1.0">
+  <groupId>dev.galasa</groupId>
+  <artifactId>dev.galasa.framework</artifactId>
+  <versioning>
+    <latest>0.36.0</latest>
+    <release>0.36.0</release>
+    <versions>
+      <version>0.35.0</version>
+      <version>0.36.0</version>
+    </versions>
+    <lastUpdated>20240101120000</lastUpdated>
+  </versioning>
+</metadata>
+`
+
+func TestMergeArtifactMetadataAddsNewVersion(t *testing.T) {
+	remote, err := parseMavenMetadata([]byte(remoteArtifactMetadata))
+	assert.Nil(t, err)
+
+	merged := mergeMavenArtifactMetadata(remote, "dev.galasa", "dev.galasa.framework", "0.37.0", "20240202120000")
+
+	assert.Equal(t, []string{"0.35.0", "0.36.0", "0.37.0"}, merged.Versioning.Versions.Version)
+	assert.Equal(t, "0.37.0", merged.Versioning.Latest)
+	assert.Equal(t, "0.37.0", merged.Versioning.Release)
+	assert.Equal(t, "20240202120000", merged.Versioning.LastUpdated)
+	assert.Equal(t, "1.1.0", merged.ModelVersion)
+}
+
+func TestMergeArtifactMetadataKeepsNewerLatestAndRelease(t *testing.T) {
+	remote, _ := parseMavenMetadata([]byte(remoteArtifactMetadata))
+
+	merged := mergeMavenArtifactMetadata(remote, "dev.galasa", "dev.galasa.framework", "0.35.1", "20240202120000")
+
+	assert.Equal(t, []string{"0.35.0", "0.36.0", "0.35.1"}, merged.Versioning.Versions.Version)
+	assert.Equal(t, "0.36.0", merged.Versioning.Latest)
+	assert.Equal(t, "0.36.0", merged.Versioning.Release)
+}
+
+func TestMergeArtifactMetadataDoesNotListVersionTwice(t *testing.T) {
+	remote, _ := parseMavenMetadata([]byte(remoteArtifactMetadata))
+
+	merged := mergeMavenArtifactMetadata(remote, "dev.galasa", "dev.galasa.framework", "0.36.0", "20240202120000")
+
+	assert.Equal(t, []string{"0.35.0", "0.36.0"}, merged.Versioning.Versions.Version)
+}
+
+func TestMergeArtifactMetadataSnapshotIsNotARelease(t *testing.T) {
+	remote, _ := parseMavenMetadata([]byte(remoteArtifactMetadata))
+
+	merged := mergeMavenArtifactMetadata(remote, "dev.galasa", "dev.galasa.framework", "0.37.0-SNAPSHOT", "20240202120000")
+
+	assert.Equal(t, "0.37.0-SNAPSHOT", merged.Versioning.Latest)
+	assert.Equal(t, "0.36.0", merged.Versioning.Release)
+}
+
+func TestMergeArtifactMetadataWhenNothingIsInRemoteRepository(t *testing.T) {
+	merged := mergeMavenArtifactMetadata(mavenMetadata{}, "dev.galasa", "dev.galasa.framework", "0.37.0", "20240202120000")
+
+	contents, err := merged.toXml()
+	assert.Nil(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>dev.galasa</groupId>
+  <artifactId>dev.galasa.framework</artifactId>
+  <versioning>
+    <latest>0.37.0</latest>
+    <release>0.37.0</release>
+    <versions>
+      <version>0.37.0</version>
+    </versions>
+    <lastUpdated>20240202120000</lastUpdated>
+  </versioning>
+</metadata>
+`, string(contents))
+}
+
+const remoteSnapshotMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata modelVersion="1.1.0">
+  <groupId>dev.galasa</groupId>
+  <artifactId>dev.galasa.framework</artifactId>
+  <version>0.37.0-SNAPSHOT</version>
+  <versioning>
+    <snapshot>
+      <timestamp>20240101.120000</timestamp>
+      <buildNumber>3</buildNumber>
+    </snapshot>
+    <lastUpdated>20240101120000</lastUpdated>
+    <snapshotVersions>
+      <snapshotVersion>
+        <extension>jar</extension>
+        <value>0.37.0-20240101.120000-3</value>
+        <updated>20240101120000</updated>
+      </snapshotVersion>
+      <snapshotVersion>
+        <classifier>sources</classifier>
+        <extension>jar</extension>
+        <value>0.37.0-20240101.120000-3</value>
+        <updated>20240101120000</updated>
+      </snapshotVersion>
+    </snapshotVersions>
+  </versioning>
+</metadata>
+`
+
+const localSnapshotMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata modelVersion="1.1.0">
+  <groupId>dev.galasa</groupId>
+  <artifactId>dev.galasa.framework</artifactId>
+  <version>0.37.0-SNAPSHOT</version>
+  <versioning>
+    <snapshot>
+      <timestamp>20240202.120000</timestamp>
+      <buildNumber>4</buildNumber>
+    </snapshot>
+    <lastUpdated>20240202120000</lastUpdated>
+    <snapshotVersions>
+      <snapshotVersion>
+        <extension>jar</extension>
+        <value>0.37.0-20240202.120000-4</value>
+        <updated>20240202120000</updated>
+      </snapshotVersion>
+      <snapshotVersion>
+        <extension>pom</extension>
+        <value>0.37.0-20240202.120000-4</value>
+        <updated>20240202120000</updated>
+      </snapshotVersion>
+    </snapshotVersions>
+  </versioning>
+</metadata>
+`
+
+func TestMergeSnapshotMetadataKeepsNewestOfEachFile(t *testing.T) {
+	remote, err := parseMavenMetadata([]byte(remoteSnapshotMetadata))
+	assert.Nil(t, err)
+	local, err := parseMavenMetadata([]byte(localSnapshotMetadata))
+	assert.Nil(t, err)
+
+	merged := mergeMavenSnapshotMetadata(remote, local, "20240202130000")
+
+	assert.Equal(t, "0.37.0-SNAPSHOT", merged.Version)
+	assert.Equal(t, "20240202.120000", merged.Versioning.Snapshot.Timestamp)
+	assert.Equal(t, 4, merged.Versioning.Snapshot.BuildNumber)
+	assert.Equal(t, "20240202130000", merged.Versioning.LastUpdated)
+	assert.Equal(t, []mavenMetadataSnapshotVersion{
+		{Extension: "jar", Value: "0.37.0-20240202.120000-4", Updated: "20240202120000"},
+		{Classifier: "sources", Extension: "jar", Value: "0.37.0-20240101.120000-3", Updated: "20240101120000"},
+		{Extension: "pom", Value: "0.37.0-20240202.120000-4", Updated: "20240202120000"},
+	}, merged.Versioning.SnapshotVersions.SnapshotVersion)
+}
+
+func TestMergeSnapshotMetadataKeepsNewerRemoteSnapshot(t *testing.T) {
+	// The local snapshot is older than the one in the remote repository, so the remote one stays the latest
+	remote, _ := parseMavenMetadata([]byte(localSnapshotMetadata))
+	local, _ := parseMavenMetadata([]byte(remoteSnapshotMetadata))
+
+	merged := mergeMavenSnapshotMetadata(remote, local, "20240202130000")
+
+	assert.Equal(t, "20240202.120000", merged.Versioning.Snapshot.Timestamp)
+	assert.Equal(t, "0.37.0-20240202.120000-4", merged.Versioning.SnapshotVersions.SnapshotVersion[0].Value)
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+// How files are uploaded to a remote maven repository.
+type mavenUploadOptions struct {
+	// How many artifacts are uploaded at the same time.
+	Parallel int
+	// How many more times an upload is tried after a 5xx response or a timeout.
+	Retries int
+	// How long to wait before the first retry. The wait doubles for each retry after that.
+	RetryDelay time.Duration
+	// How long each request may take before it times out.
+	Timeout time.Duration
+
+	// A file recording each upload which has completed, or "" if they are not recorded.
+	StateFilePath string
+	// Skip the uploads recorded in the state file by an earlier deploy.
+	IsResume bool
+}
+
+func newDefaultMavenUploadOptions() mavenUploadOptions {
+	return mavenUploadOptions{
+		Parallel:   1,
+		Retries:    3,
+		RetryDelay: time.Second,
+		Timeout:    5 * time.Minute,
+	}
+}
+
+// The remote maven repository did not give the expected response to a request.
+type mavenRequestStatusError struct {
+	Method     string
+	Url        string
+	Status     string
+	StatusCode int
+}
+
+func (err *mavenRequestStatusError) Error() string {
+	return fmt.Sprintf("%v for artifact for url %v - status line - %v", strings.ToLower(err.Method), err.Url, err.Status)
+}
+
+// Uploads files to a remote maven repository, trying again when the repository has a problem,
+// and remembering which uploads have completed. It can be used by several goroutines at once.
+type mavenUploader struct {
+	client    *http.Client
+	basicAuth string
+	options   mavenUploadOptions
+	state     *mavenUploadState
+}
+
+func newMavenUploader(fileSystem utils.FileSystem, basicAuth string, options mavenUploadOptions) (*mavenUploader, error) {
+	state, err := loadMavenUploadState(fileSystem, options.StateFilePath, options.IsResume)
+
+	uploader := &mavenUploader{
+		client:    &http.Client{Timeout: options.Timeout},
+		basicAuth: basicAuth,
+		options:   options,
+		state:     state,
+	}
+
+	return uploader, err
+}
+
+// Sends a PUT request for a file, unless an earlier deploy has already uploaded it. openBody is called
+// for each attempt, so that the whole file is sent every time.
+func (uploader *mavenUploader) put(url string, openBody func() (io.ReadCloser, error)) error {
+	var err error
+
+	if uploader.state.isCompleted(url) {
+		fmt.Printf("Skipping %v, it was uploaded by an earlier deploy\n", url)
+		return nil
+	}
+
+	err = uploader.putAlways(url, openBody)
+	if err == nil {
+		uploader.state.setCompleted(url)
+	}
+
+	return err
+}
+
+// Sends a PUT request for a file, even if it has been uploaded before, and without recording it in the state.
+// This is for files whose contents change each time they are uploaded, like a merged maven-metadata.xml.
+func (uploader *mavenUploader) putAlways(url string, openBody func() (io.ReadCloser, error)) error {
+	return uploader.retry(url, func() error {
+		body, err := openBody()
+		if err == nil {
+			err = putMavenArtifact(url, body, uploader.client, uploader.basicAuth)
+		}
+		return err
+	})
+}
+
+// Fetches a file from the remote repository. false is returned if the file isn't there.
+func (uploader *mavenUploader) get(url string) ([]byte, bool, error) {
+	var contents []byte
+	isFound := false
+
+	err := uploader.retry(url, func() error {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err == nil {
+			req.Header.Set("Authorization", uploader.basicAuth)
+
+			var resp *http.Response
+			resp, err = uploader.client.Do(req)
+			if err == nil {
+				defer resp.Body.Close()
+
+				if resp.StatusCode == http.StatusOK {
+					isFound = true
+					contents, err = io.ReadAll(resp.Body)
+				} else if resp.StatusCode != http.StatusNotFound {
+					err = &mavenRequestStatusError{Method: http.MethodGet, Url: url, Status: resp.Status, StatusCode: resp.StatusCode}
+				}
+			}
+		}
+		return err
+	})
+
+	return contents, isFound, err
+}
+
+// Calls request until it works, or fails in a way which won't go away, or there are no more retries.
+func (uploader *mavenUploader) retry(url string, request func() error) error {
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		err = request()
+
+		if err == nil || attempt >= uploader.options.Retries || !isRetryableMavenRequestError(err) {
+			break
+		}
+
+		delay := uploader.options.RetryDelay << attempt
+		fmt.Printf("Retrying %v in %v - %v\n", url, delay, err.Error())
+		time.Sleep(delay)
+	}
+
+	return err
+}
+
+// Returns true if a request failed because of a problem which might go away, a 5xx response or a timeout.
+func isRetryableMavenRequestError(err error) bool {
+	isRetryable := false
+
+	var statusErr *mavenRequestStatusError
+	var netErr net.Error
+	if errors.As(err, &statusErr) {
+		isRetryable = statusErr.StatusCode >= 500
+	} else if errors.As(err, &netErr) {
+		isRetryable = netErr.Timeout()
+	}
+
+	return isRetryable
+}
+
+// Calls upload for each index from 0 to count-1, with up to 'parallel' running at the same time.
+// Once an upload fails no more are started, and the first error is returned.
+func runMavenUploads(count int, parallel int, upload func(index int) error) error {
+	var firstErr error
+	var lock sync.Mutex
+
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	indexes := make(chan int)
+	var waitGroup sync.WaitGroup
+
+	for worker := 0; worker < parallel; worker++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for index := range indexes {
+				lock.Lock()
+				isFailed := firstErr != nil
+				lock.Unlock()
+
+				if !isFailed {
+					err := upload(index)
+					if err != nil {
+						lock.Lock()
+						if firstErr == nil {
+							firstErr = err
+						}
+						lock.Unlock()
+					}
+				}
+			}
+		}()
+	}
+
+	for index := 0; index < count; index++ {
+		indexes <- index
+	}
+	close(indexes)
+	waitGroup.Wait()
+
+	return firstErr
+}
+
+// The urls of the uploads which have completed, which are saved to a state file so that a deploy
+// which fails part way through can be resumed.
+type mavenUploadState struct {
+	lock          sync.Mutex
+	fileSystem    utils.FileSystem
+	filePath      string
+	completedUrls map[string]bool
+}
+
+// Creates the state of a deploy. When resuming, the uploads recorded in the state file are treated
+// as completed already. Otherwise the state file is started again.
+func loadMavenUploadState(fileSystem utils.FileSystem, filePath string, isResume bool) (*mavenUploadState, error) {
+	var err error
+
+	state := &mavenUploadState{
+		fileSystem:    fileSystem,
+		filePath:      filePath,
+		completedUrls: make(map[string]bool),
+	}
+
+	if isResume {
+		if filePath == "" {
+			err = errors.New("--resume needs the --state-file of the deploy to resume")
+		} else {
+			var isExisting bool
+			isExisting, err = fileSystem.Exists(filePath)
+			if err == nil && isExisting {
+				var contents string
+				contents, err = fileSystem.ReadTextFile(filePath)
+				if err == nil {
+					for _, line := range strings.Split(contents, "\n") {
+						line = strings.TrimSpace(line)
+						if line != "" {
+							state.completedUrls[line] = true
+						}
+					}
+					fmt.Printf("Resuming deploy, %v files were uploaded already\n", len(state.completedUrls))
+				}
+			}
+
+			if err != nil {
+				err = fmt.Errorf("unable to read the state file %v - %v", filePath, err.Error())
+			}
+		}
+	}
+
+	return state, err
+}
+
+func (state *mavenUploadState) isCompleted(url string) bool {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	return state.completedUrls[url]
+}
+
+func (state *mavenUploadState) setCompleted(url string) {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	state.completedUrls[url] = true
+}
+
+// Writes the url of every completed upload to the state file, one on each line.
+func (state *mavenUploadState) save() error {
+	var err error
+
+	if state.filePath != "" {
+		// Locked until the file is written, so that saves from different goroutines don't overlap.
+		state.lock.Lock()
+		defer state.lock.Unlock()
+
+		urls := make([]string, 0, len(state.completedUrls))
+		for url := range state.completedUrls {
+			urls = append(urls, url)
+		}
+
+		sort.Strings(urls)
+		contents := ""
+		if len(urls) > 0 {
+			contents = strings.Join(urls, "\n") + "\n"
+		}
+
+		err = state.fileSystem.WriteTextFile(state.filePath, contents)
+		if err != nil {
+			err = fmt.Errorf("unable to write the state file %v - %v", state.filePath, err.Error())
+		}
+		log.Printf("mavenUploadState - %v completed uploads saved to %v", len(urls), state.filePath)
+	}
+
+	return err
+}
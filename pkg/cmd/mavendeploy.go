@@ -8,7 +8,6 @@ package cmd
 import (
 	"fmt"
 	"io"
-	"io/fs"
 	"log"
 	"net/http"
 	"net/url"
@@ -29,18 +28,44 @@ var (
 	}
 
 	mavenDeployDirectory string
-	mavenDeployGroup     string
+	mavenDeployGroups    *[]string
 	mavenDeployVersion   string
+	mavenDeployIncludes  *[]string
+	mavenDeployExcludes  *[]string
+	mavenDeployIsDryRun  bool
+	mavenDeployPlanFile  string
+
+	mavenDeployUploadOptions = newDefaultMavenUploadOptions()
 )
 
 func init() {
 	mavenDeployCmd.PersistentFlags().StringVarP(&mavenDeployDirectory, "local", "", "", "local repository")
-	mavenDeployCmd.PersistentFlags().StringVarP(&mavenDeployGroup, "group", "", "", "groupId to deploy")
-	mavenDeployCmd.PersistentFlags().StringVarP(&mavenDeployVersion, "version", "", "", "version to deploy")
+	mavenDeployGroups = mavenDeployCmd.PersistentFlags().StringArrayP("group", "", nil,
+		"groupId to deploy, including its subgroups. May be a pattern, eg. dev.galasa.managers.*, and may be used more than once")
+	mavenDeployCmd.PersistentFlags().StringVarP(&mavenDeployVersion, "version", "", "",
+		"version to deploy. May be a pattern, eg. 0.36.*, or left out to deploy every version")
+	mavenDeployIncludes = mavenDeployCmd.PersistentFlags().StringArrayP("include", "", nil,
+		"only deploy the artifactIds which match this pattern. May be used more than once")
+	mavenDeployExcludes = mavenDeployCmd.PersistentFlags().StringArrayP("exclude", "", nil,
+		"do not deploy the artifactIds which match this pattern. May be used more than once")
+	mavenDeployCmd.PersistentFlags().IntVarP(&mavenDeployUploadOptions.Parallel, "parallel", "", mavenDeployUploadOptions.Parallel,
+		"the number of artifacts to upload at the same time. The versions of each artifact are uploaded one after another")
+	mavenDeployCmd.PersistentFlags().IntVarP(&mavenDeployUploadOptions.Retries, "retries", "", mavenDeployUploadOptions.Retries,
+		"how many more times to try uploading a file after a 5xx response or a timeout, waiting twice as long each time")
+	mavenDeployCmd.PersistentFlags().DurationVarP(&mavenDeployUploadOptions.Timeout, "timeout", "", mavenDeployUploadOptions.Timeout,
+		"how long each upload may take before it times out")
+	mavenDeployCmd.PersistentFlags().StringVarP(&mavenDeployUploadOptions.StateFilePath, "state-file", "", "",
+		"a file recording each upload which has completed, so that a failed deploy can be resumed")
+	mavenDeployCmd.PersistentFlags().BoolVarP(&mavenDeployUploadOptions.IsResume, "resume", "", false,
+		"skip the uploads which the --state-file records as completed by an earlier deploy")
+
+	mavenDeployCmd.PersistentFlags().BoolVarP(&mavenDeployIsDryRun, "dry-run", "", false,
+		"list the files which would be deployed, with their urls, sizes and checksums, without uploading anything")
+	mavenDeployCmd.PersistentFlags().StringVarP(&mavenDeployPlanFile, "plan-file", "", "",
+		"with --dry-run, write the list of files as json to this file instead")
 
 	mavenDeployCmd.MarkPersistentFlagRequired("local")
 	mavenDeployCmd.MarkPersistentFlagRequired("group")
-	mavenDeployCmd.MarkPersistentFlagRequired("version")
 
 	mavenCmd.AddCommand(mavenDeployCmd)
 }
@@ -50,7 +75,16 @@ func executeMavenDeploy(cmd *cobra.Command, args []string) {
 
 	fmt.Printf("executeMavenDeploy - Galasa Build - Maven Deploy - version %v\n", rootCmd.Version)
 
-	basicAuth, err := mavenGetBasicAuth()
+	var err error
+	var basicAuth string
+
+	if mavenDeployPlanFile != "" && !mavenDeployIsDryRun {
+		err = fmt.Errorf("--plan-file can only be used with --dry-run")
+	} else if !mavenDeployIsDryRun {
+		// Nothing is uploaded by a dry run, so it doesn't need credentials
+		basicAuth, err = mavenGetBasicAuth()
+	}
+
 	if err != nil {
 		exitCode = 1
 		fmt.Println(err.Error())
@@ -59,7 +93,18 @@ func executeMavenDeploy(cmd *cobra.Command, args []string) {
 
 		mavenRepositoryUrl = strings.TrimRight(mavenRepositoryUrl, "/")
 
-		err = mavenDeploy(fileSystem, mavenRepositoryUrl, mavenDeployDirectory, mavenDeployGroup, mavenDeployVersion, basicAuth)
+		selection := mavenArtifactSelection{
+			Groups:   *mavenDeployGroups,
+			Version:  mavenDeployVersion,
+			Includes: *mavenDeployIncludes,
+			Excludes: *mavenDeployExcludes,
+		}
+
+		if mavenDeployIsDryRun {
+			err = mavenDeployDryRun(fileSystem, mavenRepositoryUrl, mavenDeployDirectory, selection, mavenDeployPlanFile, os.Stdout)
+		} else {
+			err = mavenDeploy(fileSystem, mavenRepositoryUrl, mavenDeployDirectory, selection, basicAuth, mavenDeployUploadOptions)
+		}
 		if err != nil {
 			exitCode = 1
 			fmt.Println(err.Error())
@@ -74,69 +119,18 @@ func mavenDeploy(
 	fileSystem utils.FileSystem,
 	mavenRepositoryUrl string,
 	mavenDeployDirectory string,
-	mavenDeployGroup string,
-	mavenDeployVersion string,
-	basicAuth string) error {
+	selection mavenArtifactSelection,
+	basicAuth string,
+	uploadOptions mavenUploadOptions) error {
 
-	var err error
-	var artifactDirectories []fs.DirEntry
-	var mavenMetadataExists bool
-	var versionDirectoryExists bool
-
-	groupDir := strings.ReplaceAll(mavenDeployGroup, ".", string(os.PathSeparator))
-	mavenBaseDirectory := path.Join(mavenDeployDirectory, groupDir)
+	var artifacts []localMavenArtifact
 
-	artifactDirectories, err = fileSystem.ReadDir(mavenBaseDirectory)
+	err := selection.validate()
 	if err == nil {
+		artifacts, err = findLocalMavenArtifacts(fileSystem, mavenDeployDirectory, selection)
+	}
 
-		// Create a map of artifacts. Keys correspond to artifact names and values correspond to the paths to
-		// the artifacts' version directories
-		artifacts := make(map[string]string)
-
-		for _, potentialArtifact := range artifactDirectories {
-
-			// Check if this is an artifact directory and not a subgroup
-			artifactName := potentialArtifact.Name()
-			artifactDirectory := path.Join(mavenBaseDirectory, artifactName)
-			mavenMetadataFileName := "maven-metadata.xml"
-
-			mavenMetadataExists, err = fileSystem.Exists(path.Join(artifactDirectory, mavenMetadataFileName))
-			log.Printf("mavenDeploy - mavenMetadata '%v' exists is:%v", path.Join(artifactDirectory, mavenMetadataFileName), mavenMetadataExists)
-			if !mavenMetadataExists {
-				mavenMetadataPath := matchFileInDirectory(fileSystem, artifactDirectory, mavenMetadataFileName)
-
-				// No maven-metadata.xml file found within artifact directory, move on to the next artifact
-				if mavenMetadataPath == "" {
-					log.Printf("mavenDeploy - mavenMetadataPath not found for artifact: %v", potentialArtifact.Name())
-					continue
-				}
-
-			} else if err != nil {
-				log.Printf("mavenDeploy - ERROR when checking if mavenMetadataExists - %v", err.Error())
-				continue
-			}
-
-			// Check if this artifact is at the correct version
-			artifactVersionPath := path.Join(artifactDirectory, mavenDeployVersion)
-			versionDirectoryExists, err = fileSystem.DirExists(artifactVersionPath) //doens't return an error if not a dir
-			log.Printf("mavenDeploy - artifactVersionPath '%v' versionDirectoryExists: %v", artifactVersionPath, versionDirectoryExists)
-			if !versionDirectoryExists {
-				artifactVersionPath = matchFileInDirectory(fileSystem, artifactDirectory, mavenDeployVersion)
-
-				// No version directory found within the artifact directory, move on to the next artifact
-				if artifactVersionPath == "" {
-					log.Printf("mavenDeploy - artifactVersionPath not found for artifact: %v", potentialArtifact.Name())
-					continue
-				}
-
-			} else if err != nil {
-				log.Printf("mavenDeploy - ERROR when checking if versionDirectoryExists - %v", err.Error())
-				continue
-			}
-
-			artifacts[artifactName] = artifactVersionPath
-		}
-
+	if err == nil {
 		if len(artifacts) < 1 {
 			fmt.Println("No artifacts found to deploy")
 			return err
@@ -145,67 +139,123 @@ func mavenDeploy(
 		log.Printf("mavenDeploy - artifacts collected - %v", artifacts)
 
 		// Now deploy the contents of the artifact version directories
-		err = deployArtifacts(fileSystem, mavenRepositoryUrl, mavenDeployGroup, mavenDeployVersion, artifacts, basicAuth)
-
+		err = deployArtifacts(fileSystem, mavenRepositoryUrl, mavenDeployDirectory, artifacts, basicAuth, uploadOptions)
 	}
 
 	return err
 }
 
-// Deploys the given artifacts to a given Maven repository
+// Deploys the given artifacts to a given Maven repository. Several artifacts may be deployed at the same time,
+// but the files of each artifact are deployed one after another. So are the versions of each artifact, as each
+// version is merged into the same maven-metadata.xml in the remote repository.
 func deployArtifacts(
 	fileSystem utils.FileSystem,
 	mavenRepository string,
-	mavenDeployGroup string,
-	mavenDeployVersion string,
-	artifacts map[string]string,
-	basicAuth string) error {
+	localRepository string,
+	artifacts []localMavenArtifact,
+	basicAuth string,
+	uploadOptions mavenUploadOptions) error {
 
-	var err error = nil
-	var versionArtifacts []fs.DirEntry
-	var joinedUrl string
-	var file io.ReadCloser
-	client := &http.Client{}
-	groupDir := strings.ReplaceAll(mavenDeployGroup, ".", string(os.PathSeparator))
+	artifactVersions := groupMavenArtifactVersions(artifacts)
 
-	for artifactName, artifactVersionPath := range artifacts {
-		fmt.Printf("deployArtifacts - Deploying %v/%v/%v\n", mavenDeployGroup, artifactName, mavenDeployVersion)
+	uploader, err := newMavenUploader(fileSystem, basicAuth, uploadOptions)
+	if err == nil {
+		err = runMavenUploads(len(artifactVersions), uploadOptions.Parallel, func(index int) error {
+			var deployErr error
 
-		versionArtifacts, err = fileSystem.ReadDir(artifactVersionPath) //doesn't return err if dir doesn't exist
-		log.Printf("deployArtifacts - current dir is '%s'", artifactVersionPath)
-		if err == nil {
+			for _, artifact := range artifactVersions[index] {
+				fmt.Printf("deployArtifacts - Deploying %v/%v/%v\n", artifact.GroupId, artifact.ArtifactId, artifact.Version)
+
+				deployErr = deployArtifact(fileSystem, uploader, mavenRepository, localRepository, artifact.VersionPath)
+
+				// The remote metadata is only changed once all the files of the version are there
+				if deployErr == nil {
+					deployErr = deployMavenMetadata(fileSystem, uploader, mavenRepository, localRepository, artifact)
+				}
 
-			// Go through each file within the artifact's version directory and send a PUT request to deploy to the
-			// remote Maven repository
-			for _, artifactFile := range versionArtifacts {
-				fmt.Printf("Artifact File:    %v\n", artifactFile.Name())
-				artifactFilePath := path.Join(artifactVersionPath, artifactFile.Name())
-				artifactPathFromGroup := artifactFilePath[strings.Index(artifactFilePath, groupDir):]
-
-				joinedUrl, err = url.JoinPath(mavenRepository, artifactPathFromGroup)
-				if err == nil {
-					file, err = fileSystem.Open(artifactFilePath)
-					if err == nil {
-						err = putMavenArtifact(joinedUrl, file, client, basicAuth)
-						if err != nil {
-							log.Println("deployArtifacts - unable to PUT request")
-							return err
-						}
-					}
+				// Saved after each version, so a deploy which is stopped part way through can be resumed
+				saveErr := uploader.state.save()
+				if deployErr == nil {
+					deployErr = saveErr
+				}
+				if deployErr != nil {
+					break
 				}
 			}
+			return deployErr
+		})
+	}
+
+	if err == nil {
+		if len(artifacts) == 1 {
+			fmt.Printf("Complete - 1 artifact deployed\n")
+		} else {
+			fmt.Printf("Complete - %v artifacts deployed\n", len(artifacts))
 		}
 	}
 
-	if len(artifacts) == 1 {
-		fmt.Printf("Complete - 1 artifact deployed\n")
-	} else {
-		fmt.Printf("Complete - %v artifacts deployed\n", len(artifacts))
+	return err
+}
+
+// Groups the versions of each artifact together, keeping the order they are in.
+func groupMavenArtifactVersions(artifacts []localMavenArtifact) [][]localMavenArtifact {
+	var artifactVersions [][]localMavenArtifact
+	indexByArtifactPath := make(map[string]int)
+
+	for _, artifact := range artifacts {
+		artifactPath := path.Dir(artifact.VersionPath)
+		index, isGrouped := indexByArtifactPath[artifactPath]
+		if !isGrouped {
+			index = len(artifactVersions)
+			indexByArtifactPath[artifactPath] = index
+			artifactVersions = append(artifactVersions, nil)
+		}
+		artifactVersions[index] = append(artifactVersions[index], artifact)
+	}
+
+	return artifactVersions
+}
+
+// Deploys every file in the version directory of an artifact, each followed by its checksum files. Every
+// file is checked before anything is deployed, so a checksum file which doesn't match stops the deploy.
+func deployArtifact(
+	fileSystem utils.FileSystem,
+	uploader *mavenUploader,
+	mavenRepository string,
+	localRepository string,
+	artifactVersionPath string) error {
+
+	log.Printf("deployArtifact - current dir is '%s'", artifactVersionPath)
+	files, err := planArtifactFiles(fileSystem, mavenRepository, localRepository, artifactVersionPath)
+
+	// Send a PUT request for each file to deploy it to the remote Maven repository
+	for _, file := range files {
+		if err != nil {
+			break
+		}
+
+		if file.Checksums != nil {
+			fmt.Printf("Artifact File:    %v\n", path.Base(file.LocalPath))
+		} else if file.LocalPath == "" {
+			log.Printf("deployArtifact - generated checksum file %v", file.Url)
+		}
+
+		err = uploader.put(file.Url, file.opener(fileSystem))
+		if err != nil {
+			log.Println("deployArtifact - unable to PUT request")
+		}
 	}
 
 	return err
 }
 
+// Works out the url in the remote Maven repository of a file in the local repository, which is at the same
+// path from the root of the repository
+func getMavenDeployUrl(mavenRepository string, localRepository string, filePath string) (string, error) {
+	artifactPathFromRoot := strings.TrimPrefix(path.Clean(filePath), path.Clean(localRepository)+"/")
+	return url.JoinPath(mavenRepository, artifactPathFromRoot)
+}
+
 // Sends a PUT request to a Maven repository to upload an artifact to it
 func putMavenArtifact(
 	mavenRepoUrl string,
@@ -221,12 +271,13 @@ func putMavenArtifact(
 		req.Header.Set("Authorization", basicAuth)
 
 		// Send the PUT request
-		resp, err := client.Do(req)
+		var resp *http.Response
+		resp, err = client.Do(req)
 		if err == nil {
 			defer resp.Body.Close()
 
 			if resp.StatusCode != http.StatusCreated {
-				return fmt.Errorf("put for artifact for url %v - status line - %v", mavenRepoUrl, resp.Status)
+				return &mavenRequestStatusError{Method: http.MethodPut, Url: mavenRepoUrl, Status: resp.Status, StatusCode: resp.StatusCode}
 			}
 			log.Printf("putMavenArtifact - HTTP response body - %v", resp.Body)
 		}
@@ -234,25 +285,3 @@ func putMavenArtifact(
 
 	return err
 }
-
-// Walks through a given directory, searching for a given file or directory name.
-// Returns the path to the matching file or directory, or an empty string if no match was found.
-func matchFileInDirectory(fileSystem utils.FileSystem, dirPath string, targetFileName string) string {
-	var matchedPath string = ""
-	_ = fileSystem.WalkDir(dirPath, func(path string, file os.DirEntry, err error) error {
-		if file.Name() == targetFileName {
-			log.Printf("matchFileInDirectory - filename '%s' is the same as target", file.Name())
-			matchedPath = path
-
-			// Match found, no need to continue walking through the directory
-			return fs.SkipDir
-		}
-
-		return nil
-	})
-
-	if matchedPath != "" {
-		log.Printf("matchFileInDirectory - matchedPath: - %s", matchedPath)
-	}
-	return matchedPath
-}
@@ -6,10 +6,14 @@
 package cmd
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"galasa.dev/buildUtilities/pkg/utils"
 	"github.com/stretchr/testify/assert"
@@ -27,6 +31,27 @@ func createLocalArtifacts(mockFileSystem utils.FileSystem, numArtifacts int, art
 	}
 }
 
+// Answers the request for the remote maven-metadata.xml of an artifact as if it has never been deployed.
+// Returns false for any other request.
+func isMetadataRequest(writer http.ResponseWriter, req *http.Request) bool {
+	isGet := req.Method == http.MethodGet
+	if isGet {
+		writer.WriteHeader(http.StatusNotFound)
+	}
+	return isGet
+}
+
+func createTestSelection(group string, version string) mavenArtifactSelection {
+	return mavenArtifactSelection{Groups: []string{group}, Version: version}
+}
+
+// Upload options which don't keep tests waiting when a request is tried again
+func createTestUploadOptions() mavenUploadOptions {
+	options := newDefaultMavenUploadOptions()
+	options.RetryDelay = time.Millisecond
+	return options
+}
+
 func TestCanDeploySingleArtifact(t *testing.T) {
 
 	// Given...
@@ -40,7 +65,10 @@ func TestCanDeploySingleArtifact(t *testing.T) {
 	mockBasicAuth := "test"
 
 	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
-		assert.Equal(t, "/test/artifact/group/artifact-1/0.27.0/pom.xml", req.URL.Path, "Incorrect URL request")
+		if isMetadataRequest(writer, req) {
+			return
+		}
+		assert.True(t, strings.HasPrefix(req.URL.Path, "/test/artifact/group/artifact-1/"), "Incorrect URL request")
 		assert.Equal(t, "PUT", req.Method, "Incorrect HTTP method")
 		assert.Equal(t, mockBasicAuth, req.Header.Get("Authorization"), "Authorization header incorrectly set")
 
@@ -50,7 +78,7 @@ func TestCanDeploySingleArtifact(t *testing.T) {
 	defer mockServer.Close()
 
 	// When...
-	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, mockDeployGroup, mockDeployVersion, mockBasicAuth)
+	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, createTestSelection(mockDeployGroup, mockDeployVersion), mockBasicAuth, createTestUploadOptions())
 
 	// Then...
 	assert.Nil(t, err, "Failed to deploy artifact")
@@ -70,6 +98,9 @@ func TestCanDeployMultipleArtifacts(t *testing.T) {
 
 	numPutRequests := 0
 	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
 		assert.Equal(t, "PUT", req.Method, "Incorrect HTTP method")
 		assert.Equal(t, mockBasicAuth, req.Header.Get("Authorization"), "Authorization header incorrectly set")
 
@@ -80,11 +111,13 @@ func TestCanDeployMultipleArtifacts(t *testing.T) {
 	defer mockServer.Close()
 
 	// When...
-	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, mockDeployGroup, mockDeployVersion, mockBasicAuth)
+	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, createTestSelection(mockDeployGroup, mockDeployVersion), mockBasicAuth, createTestUploadOptions())
 
 	// Then...
 	assert.Nil(t, err, "Failed to deploy artifacts")
-	assert.Equal(t, 3, numPutRequests)
+
+	// Each pom.xml and merged maven-metadata.xml is deployed with its 4 checksum files
+	assert.Equal(t, 30, numPutRequests)
 }
 
 func TestCanDeployNestedArtifact(t *testing.T) {
@@ -101,7 +134,10 @@ func TestCanDeployNestedArtifact(t *testing.T) {
 	mockBasicAuth := "test"
 
 	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
-		assert.Equal(t, "/test/artifact/group/artifact-parent/artifact-1/0.27.0/pom.xml", req.URL.Path, "Incorrect URL request")
+		if isMetadataRequest(writer, req) {
+			return
+		}
+		assert.True(t, strings.HasPrefix(req.URL.Path, "/test/artifact/group/artifact-parent/artifact-1/"), "Incorrect URL request")
 		assert.Equal(t, "PUT", req.Method, "Incorrect HTTP method")
 		assert.Equal(t, mockBasicAuth, req.Header.Get("Authorization"), "Authorization header incorrectly set")
 
@@ -111,7 +147,7 @@ func TestCanDeployNestedArtifact(t *testing.T) {
 	defer mockServer.Close()
 
 	// When...
-	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, mockDeployGroup, mockDeployVersion, mockBasicAuth)
+	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, createTestSelection(mockDeployGroup, mockDeployVersion), mockBasicAuth, createTestUploadOptions())
 
 	// Then...
 	assert.Nil(t, err, "Failed to deploy artifacts")
@@ -139,6 +175,9 @@ func TestCanDeployArtifactsWithNestedArtifacts(t *testing.T) {
 
 	numPutRequests := 0
 	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
 		assert.Equal(t, "PUT", req.Method, "Incorrect HTTP method")
 		assert.Equal(t, mockBasicAuth, req.Header.Get("Authorization"), "Authorization header incorrectly set")
 
@@ -149,11 +188,13 @@ func TestCanDeployArtifactsWithNestedArtifacts(t *testing.T) {
 	defer mockServer.Close()
 
 	// When...
-	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, mockDeployGroup, mockDeployVersion, mockBasicAuth)
+	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, createTestSelection(mockDeployGroup, mockDeployVersion), mockBasicAuth, createTestUploadOptions())
 
 	// Then...
 	assert.Nil(t, err, "Failed to deploy artifacts")
-	assert.Equal(t, 5, numPutRequests)
+
+	// Each pom.xml and merged maven-metadata.xml is deployed with its 4 checksum files
+	assert.Equal(t, 50, numPutRequests)
 }
 
 func TestDoesNotDeployArtifactsWhenNoneExist(t *testing.T) {
@@ -174,6 +215,9 @@ func TestDoesNotDeployArtifactsWhenNoneExist(t *testing.T) {
 
 	numPutRequests := 0
 	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
 		assert.Equal(t, "PUT", req.Method, "Incorrect HTTP method")
 		assert.Equal(t, mockBasicAuth, req.Header.Get("Authorization"), "Authorization header incorrectly set")
 
@@ -184,7 +228,7 @@ func TestDoesNotDeployArtifactsWhenNoneExist(t *testing.T) {
 	defer mockServer.Close()
 
 	// When...
-	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, mockDeployGroup, mockDeployVersion, mockBasicAuth)
+	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, createTestSelection(mockDeployGroup, mockDeployVersion), mockBasicAuth, createTestUploadOptions())
 
 	// Then...
 	assert.Nil(t, err, "Should not deploy artifacts")
@@ -210,6 +254,9 @@ func TestDoesNotDeployArtifactWithNoVersionDirectory(t *testing.T) {
 
 	numPutRequests := 0
 	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
 		assert.Equal(t, "PUT", req.Method, "Incorrect HTTP method")
 		assert.Equal(t, mockBasicAuth, req.Header.Get("Authorization"), "Authorization header incorrectly set")
 
@@ -220,7 +267,7 @@ func TestDoesNotDeployArtifactWithNoVersionDirectory(t *testing.T) {
 	defer mockServer.Close()
 
 	// When...
-	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, mockDeployGroup, mockDeployVersion, mockBasicAuth)
+	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, createTestSelection(mockDeployGroup, mockDeployVersion), mockBasicAuth, createTestUploadOptions())
 
 	// Then...
 	assert.Nil(t, err, "Should not deploy artifact")
@@ -238,9 +285,14 @@ func TestFailingPutRequestStopsDeployingArtifacts(t *testing.T) {
 	mockDeployGroup := "test.artifact.group"
 	mockDeployVersion := "0.27.0"
 	mockBasicAuth := "test"
+	uploadOptions := createTestUploadOptions()
+	uploadOptions.Retries = 0
 
 	numPutRequests := 0
 	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
 		assert.Equal(t, "PUT", req.Method, "Incorrect HTTP method")
 		assert.Equal(t, mockBasicAuth, req.Header.Get("Authorization"), "Authorization header incorrectly set")
 
@@ -251,7 +303,7 @@ func TestFailingPutRequestStopsDeployingArtifacts(t *testing.T) {
 	defer mockServer.Close()
 
 	// When...
-	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, mockDeployGroup, mockDeployVersion, mockBasicAuth)
+	err := mavenDeploy(mockFileSystem, mockServer.URL, mockDeployDirectory, createTestSelection(mockDeployGroup, mockDeployVersion), mockBasicAuth, uploadOptions)
 
 	// Then...
 	assert.NotNil(t, err, "Put requests should have returned a HTTP 500 error")
@@ -259,3 +311,579 @@ func TestFailingPutRequestStopsDeployingArtifacts(t *testing.T) {
 	// Deployment should stop after the first PUT request
 	assert.Equal(t, 1, numPutRequests)
 }
+
+func TestDeployGeneratesMissingChecksumFiles(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	mockArtifactGroupPath := "localRepository/test/artifact/group"
+	createLocalArtifacts(mockFileSystem, 1, mockArtifactGroupPath)
+
+	var lock sync.Mutex
+	putBodies := make(map[string]string)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		lock.Lock()
+		putBodies[req.URL.Path] = string(body)
+		lock.Unlock()
+
+		writer.WriteHeader(http.StatusCreated)
+	}))
+
+	defer mockServer.Close()
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "test", createTestUploadOptions())
+
+	// Then...
+	assert.Nil(t, err, "Failed to deploy artifact")
+
+	artifactPath := "/test/artifact/group/artifact-1/0.27.0/pom.xml"
+	assert.Equal(t, 10, len(putBodies))
+	assert.Equal(t, "dummy pom.xml", putBodies[artifactPath])
+	assert.Equal(t, "bf82d5884c7bd1a86a8ed8648bc2c9e8", putBodies[artifactPath+".md5"])
+	assert.Equal(t, "f41e9246d434284669329bf73690dd639a473d55", putBodies[artifactPath+".sha1"])
+	assert.Len(t, putBodies[artifactPath+".sha256"], 64)
+	assert.Len(t, putBodies[artifactPath+".sha512"], 128)
+}
+
+func TestDeployUsesMatchingLocalChecksumFiles(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	mockArtifactGroupPath := "localRepository/test/artifact/group"
+	createLocalArtifacts(mockFileSystem, 1, mockArtifactGroupPath)
+	localSha1Path := mockArtifactGroupPath + "/artifact-1/0.27.0/pom.xml.sha1"
+	mockFileSystem.WriteTextFile(localSha1Path, "F41E9246D434284669329BF73690DD639A473D55  pom.xml\n")
+
+	var lock sync.Mutex
+	putBodies := make(map[string]string)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		lock.Lock()
+		putBodies[req.URL.Path] = string(body)
+		lock.Unlock()
+
+		writer.WriteHeader(http.StatusCreated)
+	}))
+
+	defer mockServer.Close()
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "test", createTestUploadOptions())
+
+	// Then...
+	assert.Nil(t, err, "Failed to deploy artifact")
+
+	// The local checksum file is deployed as it is, instead of a generated one
+	assert.Equal(t, 10, len(putBodies))
+	assert.Equal(t, "F41E9246D434284669329BF73690DD639A473D55  pom.xml\n", putBodies["/test/artifact/group/artifact-1/0.27.0/pom.xml.sha1"])
+}
+
+func TestDeployFailsIfLocalChecksumFileDoesNotMatch(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	mockArtifactGroupPath := "localRepository/test/artifact/group"
+	createLocalArtifacts(mockFileSystem, 1, mockArtifactGroupPath)
+	mockFileSystem.WriteTextFile(mockArtifactGroupPath+"/artifact-1/0.27.0/pom.xml.md5", "0123456789abcdef0123456789abcdef")
+
+	numPutRequests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
+		writer.WriteHeader(http.StatusCreated)
+		numPutRequests++
+	}))
+
+	defer mockServer.Close()
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "test", createTestUploadOptions())
+
+	// Then...
+	assert.NotNil(t, err, "A wrong checksum file should stop the deploy")
+	assert.Contains(t, err.Error(), "pom.xml.md5 does not match")
+
+	// Nothing should be deployed if the artifact might be corrupt
+	assert.Zero(t, numPutRequests)
+}
+
+func TestDeployRetriesAfterServerError(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifacts(mockFileSystem, 1, "localRepository/test/artifact/group")
+
+	var lock sync.Mutex
+	putCounts := make(map[string]int)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
+		lock.Lock()
+		putCounts[req.URL.Path]++
+		isFirstPut := putCounts[req.URL.Path] == 1
+		lock.Unlock()
+
+		// The pom.xml upload fails the first time only
+		if isFirstPut && strings.HasSuffix(req.URL.Path, "/pom.xml") {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			writer.WriteHeader(http.StatusCreated)
+		}
+	}))
+
+	defer mockServer.Close()
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "test", createTestUploadOptions())
+
+	// Then...
+	assert.Nil(t, err, "The failed upload should have been tried again")
+	assert.Equal(t, 2, putCounts["/test/artifact/group/artifact-1/0.27.0/pom.xml"])
+	assert.Equal(t, 1, putCounts["/test/artifact/group/artifact-1/0.27.0/pom.xml.sha1"])
+}
+
+func TestDeployGivesUpAfterRetries(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifacts(mockFileSystem, 1, "localRepository/test/artifact/group")
+
+	numPutRequests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
+		writer.WriteHeader(http.StatusBadGateway)
+		numPutRequests++
+	}))
+
+	defer mockServer.Close()
+
+	uploadOptions := createTestUploadOptions()
+	uploadOptions.Retries = 2
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "test", uploadOptions)
+
+	// Then...
+	assert.NotNil(t, err, "The upload should fail once there are no more retries")
+	assert.Contains(t, err.Error(), "502 Bad Gateway")
+	assert.Equal(t, 3, numPutRequests)
+}
+
+func TestDeployDoesNotRetryClientError(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifacts(mockFileSystem, 1, "localRepository/test/artifact/group")
+
+	numPutRequests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
+		writer.WriteHeader(http.StatusForbidden)
+		numPutRequests++
+	}))
+
+	defer mockServer.Close()
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "test", createTestUploadOptions())
+
+	// Then...
+	assert.NotNil(t, err, "A 403 response should fail the deploy")
+	assert.Equal(t, 1, numPutRequests)
+}
+
+func TestDeployRetriesAfterTimeout(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifacts(mockFileSystem, 1, "localRepository/test/artifact/group")
+
+	var lock sync.Mutex
+	numPutRequests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
+		lock.Lock()
+		numPutRequests++
+		isFirstPut := numPutRequests == 1
+		lock.Unlock()
+
+		if isFirstPut {
+			time.Sleep(500 * time.Millisecond)
+		}
+		writer.WriteHeader(http.StatusCreated)
+	}))
+
+	defer mockServer.Close()
+
+	uploadOptions := createTestUploadOptions()
+	uploadOptions.Timeout = 100 * time.Millisecond
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "test", uploadOptions)
+
+	// Then...
+	assert.Nil(t, err, "The upload which timed out should have been tried again")
+	assert.Equal(t, 11, numPutRequests)
+}
+
+func TestCanDeployArtifactsInParallel(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifacts(mockFileSystem, 8, "localRepository/test/artifact/group")
+
+	var lock sync.Mutex
+	putPaths := make(map[string]bool)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
+		lock.Lock()
+		putPaths[req.URL.Path] = true
+		lock.Unlock()
+
+		writer.WriteHeader(http.StatusCreated)
+	}))
+
+	defer mockServer.Close()
+
+	uploadOptions := createTestUploadOptions()
+	uploadOptions.Parallel = 4
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "test", uploadOptions)
+
+	// Then...
+	assert.Nil(t, err, "Failed to deploy artifacts")
+	assert.Equal(t, 80, len(putPaths))
+}
+
+func TestDeployCanResumeFromStateFile(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifacts(mockFileSystem, 3, "localRepository/test/artifact/group")
+
+	var lock sync.Mutex
+	isFailing := true
+	var putPaths []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+
+		if isFailing && strings.Contains(req.URL.Path, "/artifact-2/") {
+			writer.WriteHeader(http.StatusInternalServerError)
+		} else {
+			putPaths = append(putPaths, req.URL.Path)
+			writer.WriteHeader(http.StatusCreated)
+		}
+	}))
+
+	defer mockServer.Close()
+
+	uploadOptions := createTestUploadOptions()
+	uploadOptions.Retries = 0
+	uploadOptions.StateFilePath = "deploy-state.txt"
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "test", uploadOptions)
+
+	// Then...
+	assert.NotNil(t, err, "The deploy should fail at artifact-2")
+	assert.Equal(t, 10, len(putPaths))
+
+	// The merged metadata isn't recorded, as it is merged and uploaded again whenever its artifact is deployed
+	state, _ := mockFileSystem.ReadTextFile("deploy-state.txt")
+	assert.Equal(t, 5, strings.Count(state, "\n"))
+	assert.Contains(t, state, mockServer.URL+"/test/artifact/group/artifact-1/0.27.0/pom.xml\n")
+	assert.NotContains(t, state, "maven-metadata.xml")
+
+	// When...
+	isFailing = false
+	putPaths = nil
+	uploadOptions.IsResume = true
+	err = mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "test", uploadOptions)
+
+	// Then...
+	assert.Nil(t, err, "The resumed deploy should complete")
+	assert.Equal(t, 25, len(putPaths), "Only the files of artifact-2 and artifact-3, and the metadata, should be uploaded again")
+	for _, putPath := range putPaths {
+		if strings.Contains(putPath, "/artifact-1/") {
+			assert.Contains(t, putPath, "/artifact-1/maven-metadata.xml")
+		}
+	}
+
+	state, _ = mockFileSystem.ReadTextFile("deploy-state.txt")
+	assert.Equal(t, 15, strings.Count(state, "\n"))
+}
+
+func TestDeployResumeNeedsStateFile(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifacts(mockFileSystem, 1, "localRepository/test/artifact/group")
+
+	uploadOptions := createTestUploadOptions()
+	uploadOptions.IsResume = true
+
+	// When...
+	err := mavenDeploy(mockFileSystem, "http://localhost:1", "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "test", uploadOptions)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "--state-file")
+}
+
+func TestDeployMergesVersionIntoRemoteMetadata(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifacts(mockFileSystem, 1, "localRepository/test/artifact/group")
+
+	var lock sync.Mutex
+	putBodies := make(map[string]string)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet {
+			assert.Equal(t, "/test/artifact/group/artifact-1/maven-metadata.xml", req.URL.Path)
+			assert.Equal(t, "test", req.Header.Get("Authorization"), "Authorization header incorrectly set")
+			writer.Write([]byte(`<metadata>
+  <groupId>test.artifact.group</groupId>
+  <artifactId>artifact-1</artifactId>
+  <versioning>
+    <latest>0.26.0</latest>
+    <release>0.26.0</release>
+    <versions><version>0.26.0</version></versions>
+    <lastUpdated>20230101120000</lastUpdated>
+  </versioning>
+</metadata>`))
+			return
+		}
+
+		body, _ := io.ReadAll(req.Body)
+		lock.Lock()
+		putBodies[req.URL.Path] = string(body)
+		lock.Unlock()
+		writer.WriteHeader(http.StatusCreated)
+	}))
+
+	defer mockServer.Close()
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("test.artifact.group", "0.27.0"), "test", createTestUploadOptions())
+
+	// Then...
+	assert.Nil(t, err, "Failed to deploy artifact")
+
+	metadata, err := parseMavenMetadata([]byte(putBodies["/test/artifact/group/artifact-1/maven-metadata.xml"]))
+	assert.Nil(t, err)
+	assert.Equal(t, "artifact-1", metadata.ArtifactId)
+	assert.Equal(t, []string{"0.26.0", "0.27.0"}, metadata.Versioning.Versions.Version)
+	assert.Equal(t, "0.27.0", metadata.Versioning.Latest)
+	assert.Equal(t, "0.27.0", metadata.Versioning.Release)
+	assert.NotEqual(t, "20230101120000", metadata.Versioning.LastUpdated)
+	assert.Len(t, putBodies["/test/artifact/group/artifact-1/maven-metadata.xml.sha1"], 40)
+}
+
+// A remote repository which keeps the maven-metadata.xml files uploaded to it, so that they can be fetched
+// again. Returns the server and a function giving the versions listed in an artifact's metadata.
+func createMetadataKeepingServer(t *testing.T, metadataPutCounts map[string]int) (*httptest.Server, func(metadataPath string) []string) {
+	var lock sync.Mutex
+	metadataBodies := make(map[string][]byte)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		isMetadata := strings.HasSuffix(req.URL.Path, "/"+mavenMetadataFile)
+
+		if req.Method == http.MethodGet {
+			lock.Lock()
+			body, isFound := metadataBodies[req.URL.Path]
+			lock.Unlock()
+
+			// Slow enough that merges of the same metadata at the same time would overlap
+			time.Sleep(5 * time.Millisecond)
+
+			if isFound {
+				writer.Write(body)
+			} else {
+				writer.WriteHeader(http.StatusNotFound)
+			}
+			return
+		}
+
+		body, _ := io.ReadAll(req.Body)
+		if isMetadata {
+			lock.Lock()
+			metadataBodies[req.URL.Path] = body
+			metadataPutCounts[req.URL.Path]++
+			lock.Unlock()
+		}
+		writer.WriteHeader(http.StatusCreated)
+	}))
+
+	getVersions := func(metadataPath string) []string {
+		var versions []string
+		metadata, err := parseMavenMetadata(metadataBodies[metadataPath])
+		assert.Nil(t, err)
+		if metadata.Versioning != nil && metadata.Versioning.Versions != nil {
+			versions = metadata.Versioning.Versions.Version
+		}
+		return versions
+	}
+
+	return mockServer, getVersions
+}
+
+func TestDeployMergesEveryVersionOfAnArtifactIntoRemoteMetadata(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifact(mockFileSystem, "localRepository/dev/galasa/dev.galasa.framework", "0.36.0", "0.37.0")
+
+	metadataPutCounts := make(map[string]int)
+	mockServer, getVersions := createMetadataKeepingServer(t, metadataPutCounts)
+	defer mockServer.Close()
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("dev.galasa", ""), "test", createTestUploadOptions())
+
+	// Then...
+	assert.Nil(t, err, "Failed to deploy artifact")
+
+	metadataPath := "/dev/galasa/dev.galasa.framework/maven-metadata.xml"
+	assert.Equal(t, 2, metadataPutCounts[metadataPath], "The metadata should be merged and uploaded for each version")
+	assert.Equal(t, []string{"0.36.0", "0.37.0"}, getVersions(metadataPath))
+}
+
+func TestDeployInParallelMergesVersionsOfAnArtifactOneAfterAnother(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createLocalArtifact(mockFileSystem, "localRepository/dev/galasa/dev.galasa.framework", "0.34.0", "0.35.0", "0.36.0", "0.37.0", "0.38.0")
+	createLocalArtifact(mockFileSystem, "localRepository/dev/galasa/dev.galasa.api", "0.36.0", "0.37.0")
+
+	metadataPutCounts := make(map[string]int)
+	mockServer, getVersions := createMetadataKeepingServer(t, metadataPutCounts)
+	defer mockServer.Close()
+
+	uploadOptions := createTestUploadOptions()
+	uploadOptions.Parallel = 4
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("dev.galasa", ""), "test", uploadOptions)
+
+	// Then...
+	assert.Nil(t, err, "Failed to deploy artifacts")
+	assert.Equal(t, []string{"0.34.0", "0.35.0", "0.36.0", "0.37.0", "0.38.0"}, getVersions("/dev/galasa/dev.galasa.framework/maven-metadata.xml"))
+	assert.Equal(t, []string{"0.36.0", "0.37.0"}, getVersions("/dev/galasa/dev.galasa.api/maven-metadata.xml"))
+}
+
+func TestDeployMergesSnapshotMetadata(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	versionPath := "localRepository/dev/galasa/dev.galasa.framework/0.37.0-SNAPSHOT"
+	mockFileSystem.MkdirAll(versionPath)
+	mockFileSystem.WriteTextFile("localRepository/dev/galasa/dev.galasa.framework/maven-metadata.xml", "dummy maven-metadata.xml")
+	mockFileSystem.WriteTextFile(versionPath+"/dev.galasa.framework-0.37.0-20240202.120000-4.jar", "dummy jar")
+	mockFileSystem.WriteTextFile(versionPath+"/maven-metadata.xml", localSnapshotMetadata)
+
+	var lock sync.Mutex
+	putBodies := make(map[string]string)
+	putCounts := make(map[string]int)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet {
+			if req.URL.Path == "/dev/galasa/dev.galasa.framework/0.37.0-SNAPSHOT/maven-metadata.xml" {
+				writer.Write([]byte(remoteSnapshotMetadata))
+			} else {
+				writer.WriteHeader(http.StatusNotFound)
+			}
+			return
+		}
+
+		body, _ := io.ReadAll(req.Body)
+		lock.Lock()
+		putBodies[req.URL.Path] = string(body)
+		putCounts[req.URL.Path]++
+		lock.Unlock()
+		writer.WriteHeader(http.StatusCreated)
+	}))
+
+	defer mockServer.Close()
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", createTestSelection("dev.galasa", "0.37.0-SNAPSHOT"), "test", createTestUploadOptions())
+
+	// Then...
+	assert.Nil(t, err, "Failed to deploy artifact")
+
+	snapshotMetadata, err := parseMavenMetadata([]byte(putBodies["/dev/galasa/dev.galasa.framework/0.37.0-SNAPSHOT/maven-metadata.xml"]))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, snapshotMetadata.Versioning.Snapshot.BuildNumber)
+	assert.Len(t, snapshotMetadata.Versioning.SnapshotVersions.SnapshotVersion, 3, "The sources of the earlier snapshot should still be listed")
+	assert.Equal(t, 1, putCounts["/dev/galasa/dev.galasa.framework/0.37.0-SNAPSHOT/maven-metadata.xml"],
+		"The local snapshot metadata should not be uploaded as it is")
+
+	artifactMetadata, err := parseMavenMetadata([]byte(putBodies["/dev/galasa/dev.galasa.framework/maven-metadata.xml"]))
+	assert.Nil(t, err)
+	assert.Equal(t, "dev.galasa", artifactMetadata.GroupId)
+	assert.Equal(t, []string{"0.37.0-SNAPSHOT"}, artifactMetadata.Versioning.Versions.Version)
+	assert.Equal(t, "", artifactMetadata.Versioning.Release)
+
+	// The jar and merged snapshot metadata and artifact metadata, each with 4 checksum files
+	assert.Equal(t, 15, len(putBodies))
+}
+
+func TestCanDeploySeveralGroups(t *testing.T) {
+
+	// Given...
+	mockFileSystem := createLocalRepository()
+
+	var lock sync.Mutex
+	putPaths := make(map[string]bool)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if isMetadataRequest(writer, req) {
+			return
+		}
+
+		lock.Lock()
+		putPaths[req.URL.Path] = true
+		lock.Unlock()
+		writer.WriteHeader(http.StatusCreated)
+	}))
+
+	defer mockServer.Close()
+
+	selection := mavenArtifactSelection{
+		Groups:   []string{"dev.galasa.managers.*", "org.example"},
+		Version:  "0.36.*",
+		Excludes: []string{"*cloud*"},
+	}
+
+	// When...
+	err := mavenDeploy(mockFileSystem, mockServer.URL, "localRepository", selection, "test", createTestUploadOptions())
+
+	// Then...
+	assert.Nil(t, err, "Failed to deploy artifacts")
+	assert.True(t, putPaths["/dev/galasa/managers/zos/dev.galasa.zos.manager/0.36.0/pom.xml"])
+	assert.True(t, putPaths["/dev/galasa/managers/zos/dev.galasa.zos.manager/maven-metadata.xml"])
+	assert.True(t, putPaths["/org/example/example.lib/0.36.0/pom.xml"])
+	assert.Equal(t, 20, len(putPaths))
+}
@@ -0,0 +1,23 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseCmd = &cobra.Command{
+		Use:   "release",
+		Short: "release metadata related commands",
+		Long:  "Various commands to create and check Galasa release metadata files",
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(releaseCmd)
+}
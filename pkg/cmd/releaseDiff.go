@@ -0,0 +1,53 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package cmd
+
+import (
+	"galasa.dev/buildUtilities/pkg/releases"
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseDiffOldFile string
+	releaseDiffNewFile string
+	releaseDiffFormat  string
+
+	releaseDiffCmd = &cobra.Command{
+		Use:   "diff",
+		Short: "Compares two release metadata files.",
+		Long: "Compares two release metadata files, and lists the bundles which were added, removed or changed version" +
+			" in each section, as markdown for release notes or as json.",
+		Run: releaseDiffExecute,
+	}
+)
+
+func init() {
+	releaseDiffCmd.PersistentFlags().StringVarP(&releaseDiffOldFile, "old", "", "",
+		"The release metadata file of the earlier release.")
+	releaseDiffCmd.MarkPersistentFlagRequired("old")
+
+	releaseDiffCmd.PersistentFlags().StringVarP(&releaseDiffNewFile, "new", "", "",
+		"The release metadata file of the later release.")
+	releaseDiffCmd.MarkPersistentFlagRequired("new")
+
+	releaseDiffCmd.PersistentFlags().StringVarP(&releaseDiffFormat, "format", "f", releases.DIFF_FORMAT_MARKDOWN,
+		"The format of the differences. One of 'markdown' or 'json'.")
+
+	releaseCmd.AddCommand(releaseDiffCmd)
+}
+
+func releaseDiffExecute(cmd *cobra.Command, args []string) {
+
+	fs := utils.NewOSFileSystem()
+	err := releases.DiffExecute(fs, releaseDiffOldFile, releaseDiffNewFile, releaseDiffFormat)
+
+	if err != nil {
+		panic(err)
+	}
+
+}
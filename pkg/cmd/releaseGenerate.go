@@ -0,0 +1,63 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package cmd
+
+import (
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"galasa.dev/buildUtilities/pkg/releases"
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseGenerateSourceFolderPath string
+	releaseGeneratePreviousFile     string
+	releaseGenerateReleaseVersion   string
+	releaseGenerateOutputFile       string
+	releaseGenerateSection          string
+
+	releaseGenerateCmd = &cobra.Command{
+		Use:   "generate",
+		Short: "Generates a release metadata file from the modules in a source tree.",
+		Long: "Generates a release metadata file from the modules in a source tree." +
+			" The flags of bundles in a previous release file are kept, and added or removed bundles are reported.",
+		Run: releaseGenerateExecute,
+	}
+)
+
+func init() {
+	releaseGenerateCmd.PersistentFlags().StringVarP(&releaseGenerateSourceFolderPath, SOURCE_FOLDER_PATH, "p", "",
+		"Path to the source tree to find modules in.")
+	releaseGenerateCmd.MarkPersistentFlagRequired(SOURCE_FOLDER_PATH)
+
+	releaseGenerateCmd.PersistentFlags().StringVarP(&releaseGenerateOutputFile, "output", "o", "",
+		"The release metadata file to write.")
+	releaseGenerateCmd.MarkPersistentFlagRequired("output")
+
+	releaseGenerateCmd.PersistentFlags().StringVarP(&releaseGeneratePreviousFile, "previous", "", "",
+		"Optional. A previous release metadata file to merge with. It may be the same file as the --output file.")
+
+	releaseGenerateCmd.PersistentFlags().StringVarP(&releaseGenerateReleaseVersion, "release-version", "", "",
+		"The version of the release. Needed unless the --previous file has a release version, which this replaces.")
+
+	releaseGenerateCmd.PersistentFlags().StringVarP(&releaseGenerateSection, "section", "", galasayaml.SECTION_FRAMEWORK,
+		"The section which new bundles are added to. One of 'framework', 'api' or 'managers'.")
+
+	releaseCmd.AddCommand(releaseGenerateCmd)
+}
+
+func releaseGenerateExecute(cmd *cobra.Command, args []string) {
+
+	fs := utils.NewOSFileSystem()
+	err := releases.GenerateExecute(fs, releaseGenerateSourceFolderPath, releaseGeneratePreviousFile, releaseGenerateReleaseVersion,
+		releaseGenerateOutputFile, releaseGenerateSection)
+
+	if err != nil {
+		panic(err)
+	}
+
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"galasa.dev/buildUtilities/pkg/releases"
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseValidateFiles *[]string
+
+	releaseValidateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Checks that release metadata files are well formed.",
+		Long: "Checks that release metadata files are well formed." +
+			" Unknown fields, missing fields, bad versions and duplicate bundles are reported with the file and line," +
+			" and the exit code is non-zero.",
+		Run: releaseValidateExecute,
+	}
+)
+
+func init() {
+	releaseValidateFiles = releaseValidateCmd.PersistentFlags().StringArrayP("release", "r", nil,
+		"A release metadata file to check. May be used more than once.")
+	releaseValidateCmd.MarkPersistentFlagRequired("release")
+
+	releaseCmd.AddCommand(releaseValidateCmd)
+}
+
+func releaseValidateExecute(cmd *cobra.Command, args []string) {
+	var exitCode = 0
+
+	fs := utils.NewOSFileSystem()
+	err := releases.ValidateExecute(fs, *releaseValidateFiles)
+
+	if err != nil {
+		exitCode = 1
+		fmt.Println(err.Error())
+	}
+
+	os.Exit(exitCode)
+}
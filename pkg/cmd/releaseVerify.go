@@ -0,0 +1,202 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"galasa.dev/buildUtilities/pkg/releases"
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseVerifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "Checks that every bundle of a release is in a maven repository.",
+		Long: "Checks that every bundle of a release exists at its version in a maven repository." +
+			" Missing artifacts are reported, and the exit code is non-zero.",
+		Run: releaseVerifyExecute,
+	}
+
+	releaseVerifyFiles         *[]string
+	releaseVerifyRepositoryUrl string
+	releaseVerifyParallel      int
+)
+
+// An artifact of a release which could not be found in the maven repository.
+type missingMavenArtifact struct {
+	Url    string
+	Reason string
+}
+
+func init() {
+	releaseVerifyFiles = releaseVerifyCmd.PersistentFlags().StringArrayP("release", "r", nil,
+		"A release metadata file. May be used more than once, in which case the files are merged.")
+	releaseVerifyCmd.PersistentFlags().StringVarP(&releaseVerifyRepositoryUrl, "repository", "", "", "repository")
+	releaseVerifyCmd.PersistentFlags().IntVarP(&releaseVerifyParallel, "parallel", "", 10,
+		"The number of artifacts to check at the same time.")
+
+	// The same credentials as the maven commands. They are optional, as many repositories can be read without them.
+	releaseVerifyCmd.PersistentFlags().StringVarP(&mavenUsername, "username", "", "", "username")
+	releaseVerifyCmd.PersistentFlags().StringVarP(&mavenPassword, "password", "", "", "password")
+	releaseVerifyCmd.PersistentFlags().StringVarP(&mavenCredentials, "credentials", "", "", "credentials file")
+
+	releaseVerifyCmd.MarkPersistentFlagRequired("release")
+	releaseVerifyCmd.MarkPersistentFlagRequired("repository")
+
+	releaseCmd.AddCommand(releaseVerifyCmd)
+}
+
+func releaseVerifyExecute(cmd *cobra.Command, args []string) {
+	var exitCode = 0
+	var err error
+	var release galasayaml.Release
+
+	basicAuth := ""
+	if mavenUsername != "" || mavenPassword != "" || mavenCredentials != "" {
+		basicAuth, err = mavenGetBasicAuth()
+	}
+
+	if err == nil {
+		var conflicts []galasayaml.MergeConflict
+		release, conflicts, err = releases.ReadAndMergeReleaseFiles(utils.NewOSFileSystem(), *releaseVerifyFiles,
+			galasayaml.MERGE_STRATEGY_LAST_WINS)
+		for _, conflict := range conflicts {
+			fmt.Printf("Conflict: %v\n", conflict)
+		}
+	}
+
+	if err == nil {
+		repositoryUrl := strings.TrimRight(releaseVerifyRepositoryUrl, "/")
+		err = verifyReleaseArtifacts(&http.Client{}, repositoryUrl, basicAuth, release, releaseVerifyParallel)
+	}
+
+	if err != nil {
+		exitCode = 1
+		fmt.Println(err.Error())
+	}
+
+	os.Exit(exitCode)
+}
+
+// Checks that every bundle in the release exists in the maven repository, using a HEAD request for
+// each one. Up to 'parallel' requests are made at the same time.
+func verifyReleaseArtifacts(
+	client *http.Client,
+	mavenRepositoryUrl string,
+	basicAuth string,
+	release galasayaml.Release,
+	parallel int) error {
+
+	var err error
+	var artifactUrls []string
+
+	for _, sectionName := range galasayaml.SectionNames {
+		for _, bundle := range *release.GetSectionBundles(sectionName) {
+			var artifactUrl string
+			artifactUrl, err = getMavenArtifactUrl(mavenRepositoryUrl, bundle)
+			if err != nil {
+				return err
+			}
+			artifactUrls = append(artifactUrls, artifactUrl)
+		}
+	}
+
+	missingArtifacts := headMavenArtifacts(client, artifactUrls, basicAuth, parallel)
+
+	for _, missing := range missingArtifacts {
+		fmt.Printf("Missing %v - %v\n", missing.Url, missing.Reason)
+	}
+	fmt.Printf("%v artifacts checked, %v missing\n", len(artifactUrls), len(missingArtifacts))
+
+	if len(missingArtifacts) > 0 {
+		err = fmt.Errorf("%v artifacts are missing from %v", len(missingArtifacts), mavenRepositoryUrl)
+	}
+
+	return err
+}
+
+// Works out the url of the file for a bundle, eg. {repository}/dev/galasa/dev.galasa/0.36.0/dev.galasa-0.36.0.jar
+func getMavenArtifactUrl(mavenRepositoryUrl string, bundle galasayaml.Bundle) (string, error) {
+	artifactType := bundle.Type
+	if artifactType == "" {
+		artifactType = "jar"
+	}
+
+	groupPath := strings.ReplaceAll(bundle.GetGroup(), ".", "/")
+	fileName := fmt.Sprintf("%v-%v.%v", bundle.Artifact, bundle.Version, artifactType)
+
+	return url.JoinPath(mavenRepositoryUrl, groupPath, bundle.Artifact, bundle.Version, fileName)
+}
+
+// Sends a HEAD request for each url, returning those which could not be found, in the same order as the urls.
+func headMavenArtifacts(client *http.Client, artifactUrls []string, basicAuth string, parallel int) []missingMavenArtifact {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	reasons := make([]string, len(artifactUrls))
+	indexes := make(chan int)
+	var waitGroup sync.WaitGroup
+
+	for worker := 0; worker < parallel; worker++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for index := range indexes {
+				reasons[index] = headMavenArtifact(client, artifactUrls[index], basicAuth)
+			}
+		}()
+	}
+
+	for index := range artifactUrls {
+		indexes <- index
+	}
+	close(indexes)
+	waitGroup.Wait()
+
+	var missingArtifacts []missingMavenArtifact
+	for index, reason := range reasons {
+		if reason != "" {
+			missingArtifacts = append(missingArtifacts, missingMavenArtifact{Url: artifactUrls[index], Reason: reason})
+		}
+	}
+	return missingArtifacts
+}
+
+// Sends a HEAD request for an artifact, returning why it could not be found, or "" if it exists.
+func headMavenArtifact(client *http.Client, artifactUrl string, basicAuth string) string {
+	reason := ""
+
+	req, err := http.NewRequest(http.MethodHead, artifactUrl, nil)
+	if err == nil {
+		if basicAuth != "" {
+			req.Header.Set("Authorization", basicAuth)
+		}
+
+		var resp *http.Response
+		resp, err = client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				reason = "status line - " + resp.Status
+			}
+		}
+	}
+
+	if err != nil {
+		reason = err.Error()
+	}
+
+	return reason
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func createReleaseToVerify() galasayaml.Release {
+	var release galasayaml.Release
+	release.Framework.Bundles = []galasayaml.Bundle{
+		{Artifact: "dev.galasa.framework", Version: "0.36.0"},
+	}
+	release.Managers.Bundles = []galasayaml.Bundle{
+		{Artifact: "dev.galasa.zos.manager", Version: "0.36.0"},
+	}
+	release.External.Bundles = []galasayaml.Bundle{
+		{Group: "org.example", Artifact: "ext", Version: "1.0.0", Type: "pom"},
+	}
+	return release
+}
+
+func TestCanVerifyReleaseArtifactsExist(t *testing.T) {
+
+	// Given...
+	mockBasicAuth := "test"
+	var requestedPaths sync.Map
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "HEAD", req.Method, "Incorrect HTTP method")
+		assert.Equal(t, mockBasicAuth, req.Header.Get("Authorization"), "Authorization header incorrectly set")
+		requestedPaths.Store(req.URL.Path, true)
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+
+	defer mockServer.Close()
+
+	// When...
+	err := verifyReleaseArtifacts(mockServer.Client(), mockServer.URL, mockBasicAuth, createReleaseToVerify(), 2)
+
+	// Then...
+	assert.Nil(t, err, "Failed to verify artifacts")
+	for _, expectedPath := range []string{
+		"/dev/galasa/dev.galasa.framework/0.36.0/dev.galasa.framework-0.36.0.jar",
+		"/dev/galasa/dev.galasa.zos.manager/0.36.0/dev.galasa.zos.manager-0.36.0.jar",
+		"/org/example/ext/1.0.0/ext-1.0.0.pom",
+	} {
+		_, isRequested := requestedPaths.Load(expectedPath)
+		assert.True(t, isRequested, expectedPath)
+	}
+}
+
+func TestVerifyReportsMissingArtifacts(t *testing.T) {
+
+	// Given...
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "", req.Header.Get("Authorization"), "Authorization header should not be set")
+
+		if req.URL.Path == "/dev/galasa/dev.galasa.zos.manager/0.36.0/dev.galasa.zos.manager-0.36.0.jar" {
+			writer.WriteHeader(http.StatusNotFound)
+		} else {
+			writer.WriteHeader(http.StatusOK)
+		}
+	}))
+
+	defer mockServer.Close()
+
+	// When...
+	err := verifyReleaseArtifacts(mockServer.Client(), mockServer.URL, "", createReleaseToVerify(), 10)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "1 artifacts are missing")
+}
+
+func TestHeadMavenArtifactsReturnsMissingArtifactsInOrder(t *testing.T) {
+
+	// Given...
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/ok" {
+			writer.WriteHeader(http.StatusOK)
+		} else {
+			writer.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	defer mockServer.Close()
+
+	urls := []string{mockServer.URL + "/a", mockServer.URL + "/ok", mockServer.URL + "/b", mockServer.URL + "/c"}
+
+	// When...
+	missing := headMavenArtifacts(mockServer.Client(), urls, "", 3)
+
+	// Then...
+	assert.Len(t, missing, 3)
+	assert.Equal(t, mockServer.URL+"/a", missing[0].Url)
+	assert.Equal(t, mockServer.URL+"/b", missing[1].Url)
+	assert.Equal(t, mockServer.URL+"/c", missing[2].Url)
+	assert.Contains(t, missing[0].Reason, "500")
+}
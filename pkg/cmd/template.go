@@ -7,21 +7,23 @@
 package cmd
 
 import (
-	"bytes"
 	"fmt"
-	"io/ioutil"
-	"text/template"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 
 	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"galasa.dev/buildUtilities/pkg/releases"
+	"galasa.dev/buildUtilities/pkg/utils"
 )
 
 var (
 	templateFile        string
 	releaseMetadata     *[]string
 	outputFile          string
+	templateSelect      string
 	requireObr          bool
 	requireBom          bool
 	requireMvp          bool
@@ -37,26 +39,31 @@ var (
 		Run:   templateExecute,
 	}
 
-	release galasayaml.Release
+	templateMergeStrategy string
+	templateManifest      string
+	templateDir           string
+	templateCheck         bool
 )
 
-type templateData struct {
-	Release     string
-	Artifacts   []artifact
-	BootRelease string
-}
-type artifact struct {
-	GroupId    string
-	ArtifactId string
-	Version    string
-	Type       string
-}
-
 func init() {
 	templateCmd.PersistentFlags().StringVarP(&templateFile, "template", "t", "", "template file")
 	releaseMetadata = templateCmd.PersistentFlags().StringArrayP("releaseMetadata", "r", nil, "release metadata files")
-	templateCmd.PersistentFlags().StringVarP(&outputFile, "output", "o", "", "output file")
-
+	templateCmd.PersistentFlags().StringVarP(&outputFile, "output", "o", "", "output file, or - to write to stdout")
+	templateCmd.PersistentFlags().StringVarP(&templateManifest, "manifest", "m", "",
+		"a yaml file listing the template, output and selector of each file to generate. "+
+			"Use instead of --template, --output and the artifact selection flags.")
+	templateCmd.PersistentFlags().StringVarP(&templateDir, "template-dir", "", "",
+		"a folder of templates which can be included with {{ template \"name\" . }}, where name is the file name without its extension")
+	templateCmd.PersistentFlags().StringVarP(&templateMergeStrategy, "merge-strategy", "", galasayaml.MERGE_STRATEGY_LAST_WINS,
+		"how to choose between bundles with the same group:artifact in different release metadata files, one of "+
+			strings.Join(galasayaml.MergeStrategies, ", "))
+	templateCmd.PersistentFlags().BoolVarP(&templateCheck, "check", "", false,
+		"compare the rendered templates with the existing output files instead of writing them, "+
+			"printing a diff and failing if any are out of date")
+
+	templateCmd.PersistentFlags().StringVarP(&templateSelect, "select", "", "",
+		"an expression which picks the maven artifacts to use, eg. \"section in [framework,api] && bom && !isolated\". "+
+			"Use instead of the artifact type flags below, which are presets for common expressions.")
 	templateCmd.PersistentFlags().BoolVarP(&requireObr, "obr", "", false, "require maven artifacts for OBR")
 	templateCmd.PersistentFlags().BoolVarP(&requireBom, "bom", "", false, "require maven artifacts for BOM")
 	templateCmd.PersistentFlags().BoolVarP(&requireMvp, "mvp", "", false, "require maven artifacts for mvp zip")
@@ -69,282 +76,57 @@ func init() {
 }
 
 func templateExecute(cmd *cobra.Command, args []string) {
-	fmt.Printf("Galasa Build - Template - version %v\n", rootCmd.Version)
-
-	if releaseMetadata == nil {
-		panic("Release metadata files have not been provided")
-	}
-
-	if templateFile == "" {
-		panic("Template file has not been provided")
-	}
-
-	if outputFile == "" {
-		fmt.Println("Output file has not been provided")
-	}
-
-	// Read in all the metadata files,
-	initial := true
-	for _, inputFile := range *releaseMetadata {
-		var inputRelease galasayaml.Release
-
-		b, err := ioutil.ReadFile(inputFile)
-		if err != nil {
-			panic(err)
-		}
-
-		err = yaml.Unmarshal(b, &inputRelease)
-		if err != nil {
-			panic(err)
-		}
-
-		if initial {
-			release = inputRelease
-			initial = false
-		} else {
-			if inputRelease.Release.Version != "" {
-				release.Release.Version = inputRelease.Release.Version
-			}
-
-			for _, inputBundle := range inputRelease.Framework.Bundles {
-				release.Framework.Bundles = append(release.Framework.Bundles, inputBundle)
-			}
-
-			for _, inputBundle := range inputRelease.Api.Bundles {
-				release.Framework.Bundles = append(release.Framework.Bundles, inputBundle)
-			}
-
-			for _, inputBundle := range inputRelease.Managers.Bundles {
-				release.Framework.Bundles = append(release.Framework.Bundles, inputBundle)
-			}
-
-			for _, inputBundle := range inputRelease.External.Bundles {
-				release.Framework.Bundles = append(release.Framework.Bundles, inputBundle)
-			}
-		}
+	var exitCode = 0
 
+	// When the rendered template goes to stdout, everything else goes to stderr so it can be piped.
+	var progress io.Writer = os.Stdout
+	if outputFile == releases.STDOUT_OUTPUT_FILE_PATH {
+		progress = os.Stderr
 	}
 
-	if release.Release.Version == "" {
-		panic("Release version not provided")
-	}
+	fmt.Fprintf(progress, "Galasa Build - Template - version %v\n", rootCmd.Version)
 
-	var requires = 0
-	if requireObr {
-		requires++
-		fmt.Println("OBR artifact type requested")
-	}
-	if requireBom {
-		requires++
-		fmt.Println("BOM artifact type requested")
-	}
-	if requireMvp {
-		requires++
-		fmt.Println("MVP artifact type requested")
-	}
-	if requireIsolated {
-		requires++
-		fmt.Println("Isolated artifact type requested")
-	}
-	if requireJavadoc {
-		requires++
-		fmt.Println("Javadoc artifact type requested")
-	}
-	if requireManagerdoc {
-		requires++
-		fmt.Println("Manager Docs artifact type requested")
+	options := releases.TemplateOptions{
+		ReleaseMetadataFilePaths: *releaseMetadata,
+		MergeStrategy:            templateMergeStrategy,
+		TemplateFilePath:         templateFile,
+		OutputFilePath:           outputFile,
+		Selector:                 templateSelect,
+		Presets:                  getRequestedArtifactTypes(progress),
+		ManifestFilePath:         templateManifest,
+		TemplateFolderPath:       templateDir,
+		IsCheckMode:              templateCheck,
 	}
 
-	if requireCodeCoverage {
-		requires++
-		fmt.Println("Code Coverage artifact type requested")
-	}
-
-	if requires == 0 {
-		panic("Artifact type has not been provided")
-	}
-
-	if requires > 1 {
-		panic("Too many artifact types have been requested")
-	}
+	fs := utils.NewOSFileSystem()
+	err := releases.TemplateExecute(fs, options, os.Stdout, progress)
 
-	t := templateData{}
-
-	t.Release = release.Release.Version
-	fmt.Printf("Release version is %v\n", t.Release)
-
-	for _, bundle := range release.Framework.Bundles {
-		if bundle.Group == "" {
-			bundle.Group = "dev.galasa"
-		}
-
-		selected := false
-
-		if requireObr {
-			selected = bundle.Obr
-		} else if requireBom {
-			selected = bundle.Bom
-		} else if requireMvp {
-			selected = bundle.Mvp
-		} else if requireIsolated {
-			selected = true
-		} else if requireJavadoc {
-			selected = bundle.Javadoc
-		} else if requireManagerdoc {
-			selected = bundle.Managerdoc
-		} else if requireCodeCoverage {
-			selected = bundle.Codecoverage
-		}
-
-		if selected {
-			artifact := artifact{
-				GroupId:    bundle.Group,
-				ArtifactId: bundle.Artifact,
-				Version:    bundle.Version,
-				Type:       bundle.Type,
-			}
-
-			t.Artifacts = append(t.Artifacts, artifact)
-
-			fmt.Printf("    Added framework artifact %v:%v:%v\n", artifact.GroupId, artifact.ArtifactId, artifact.Version)
-		}
-
-		if bundle.Artifact == "galasa-boot" {
-			t.BootRelease = bundle.Version
-			fmt.Printf("    Set galasa-boot version to %v\n", bundle.Version)
-		}
-	}
-
-	for _, bundle := range release.Api.Bundles {
-		if bundle.Group == "" {
-			bundle.Group = "dev.galasa"
-		}
-
-		selected := false
-
-		if requireObr {
-			selected = bundle.Obr
-		} else if requireBom {
-			selected = bundle.Bom
-		} else if requireMvp {
-			selected = bundle.Mvp
-		} else if requireIsolated {
-			selected = true
-		} else if requireJavadoc {
-			selected = bundle.Javadoc
-		} else if requireManagerdoc {
-			selected = bundle.Managerdoc
-		} else if requireCodeCoverage {
-			selected = bundle.Codecoverage
-		}
-
-		if selected {
-			artifact := artifact{
-				GroupId:    bundle.Group,
-				ArtifactId: bundle.Artifact,
-				Version:    bundle.Version,
-				Type:       bundle.Type,
-			}
-
-			t.Artifacts = append(t.Artifacts, artifact)
-
-			fmt.Printf("    Added framework artifact %v:%v:%v\n", artifact.GroupId, artifact.ArtifactId, artifact.Version)
-		}
-	}
-
-	for _, bundle := range release.Managers.Bundles {
-		if bundle.Group == "" {
-			bundle.Group = "dev.galasa"
-		}
-
-		selected := false
-
-		if requireObr {
-			selected = bundle.Obr
-		} else if requireBom {
-			selected = bundle.Bom
-		} else if requireMvp {
-			selected = bundle.Mvp
-		} else if requireIsolated {
-			selected = true
-		} else if requireJavadoc {
-			selected = true
-		} else if requireManagerdoc {
-			selected = true
-		} else if requireCodeCoverage {
-			selected = bundle.Codecoverage
-		}
-
-		if selected {
-			artifact := artifact{
-				GroupId:    bundle.Group,
-				ArtifactId: bundle.Artifact,
-				Version:    bundle.Version,
-				Type:       bundle.Type,
-			}
-
-			t.Artifacts = append(t.Artifacts, artifact)
-
-			fmt.Printf("    Added framework artifact %v:%v:%v\n", artifact.GroupId, artifact.ArtifactId, artifact.Version)
-		}
-	}
-
-	for _, bundle := range release.External.Bundles {
-		if bundle.Group == "" {
-			bundle.Group = "dev.galasa"
-		}
-
-		selected := false
-
-		if requireObr {
-			selected = bundle.Obr
-		} else if requireBom {
-			selected = bundle.Bom
-		} else if requireMvp {
-			selected = bundle.Mvp
-		} else if requireIsolated {
-			selected = bundle.Isolated
-		} else if requireJavadoc {
-			selected = false
-		} else if requireManagerdoc {
-			selected = false
-		} else if requireCodeCoverage {
-			selected = bundle.Codecoverage
-		}
-
-		if selected {
-			artifact := artifact{
-				GroupId:    bundle.Group,
-				ArtifactId: bundle.Artifact,
-				Version:    bundle.Version,
-				Type:       bundle.Type,
-			}
-
-			t.Artifacts = append(t.Artifacts, artifact)
-
-			fmt.Printf("    Added framework artifact %v:%v:%v\n", artifact.GroupId, artifact.ArtifactId, artifact.Version)
-		}
-	}
-
-	b, err := ioutil.ReadFile(templateFile)
 	if err != nil {
-		panic(err)
+		exitCode = 1
+		fmt.Fprintln(progress, err.Error())
 	}
 
-	templString := string(b)
+	os.Exit(exitCode)
+}
 
-	tmpl, err := template.New("convert").Parse(templString)
-	if err != nil {
-		panic(err)
+// getRequestedArtifactTypes returns the names of the artifact type flags which were set.
+func getRequestedArtifactTypes(progress io.Writer) []string {
+	artifactTypeFlags := map[string]bool{
+		"obr":          requireObr,
+		"bom":          requireBom,
+		"mvp":          requireMvp,
+		"isolated":     requireIsolated,
+		"javadoc":      requireJavadoc,
+		"managerdoc":   requireManagerdoc,
+		"codecoverage": requireCodeCoverage,
 	}
 
-	var buf bytes.Buffer
-
-	err = tmpl.Execute(&buf, t)
-	if err != nil {
-		panic(err)
+	var requested []string
+	for _, presetName := range releases.GetSelectorPresetNames() {
+		if artifactTypeFlags[presetName] {
+			requested = append(requested, presetName)
+			fmt.Fprintf(progress, "%v artifact type requested\n", presetName)
+		}
 	}
-
-	ioutil.WriteFile(outputFile, buf.Bytes(), 0644)
-
+	return requested
 }
@@ -23,9 +23,13 @@ var (
 const SOURCE_FOLDER_PATH = "sourcefolderpath"
 
 func init() {
-	// The --sourcefolderepath flag. Refers to the top-level source folder to process.
-	versioningCmd.PersistentFlags().StringVarP(&sourceCodeFolderPath, SOURCE_FOLDER_PATH, "p", "", "Path to the source tree to manipulate.")
-	versioningCmd.MarkPersistentFlagRequired(SOURCE_FOLDER_PATH)
-
 	rootCmd.AddCommand(versioningCmd)
 }
+
+// addSourceFolderPathFlag adds the mandatory --sourcefolderpath flag, which refers to the top-level source
+// folder to process, to a versioning command. Commands which don't work on a source folder, like
+// 'versioning undo', don't have it.
+func addSourceFolderPathFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&sourceCodeFolderPath, SOURCE_FOLDER_PATH, "p", "", "Path to the source tree to manipulate.")
+	cmd.MarkPersistentFlagRequired(SOURCE_FOLDER_PATH)
+}
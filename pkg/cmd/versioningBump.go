@@ -0,0 +1,63 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package cmd
+
+import (
+	"galasa.dev/buildUtilities/pkg/utils"
+	"galasa.dev/buildUtilities/pkg/versioning"
+	"github.com/spf13/cobra"
+)
+
+var (
+	versionBumpPart    string
+	versionBumpSuffix  string
+	versionBumpDryRun  bool
+	versionBumpJournal string
+
+	versioningBumpCmd = &cobra.Command{
+		Use:   "bump",
+		Short: "Increments the major, minor or patch part of the source module versions recursively.",
+		Long: "Increments the major, minor or patch part of the source module versions recursively." +
+			" Any existing suffix is kept, unless the --suffix flag is used to replace it." +
+			" Dependencies on the modules are changed to the new versions.",
+		Run: versioningBumpExecute,
+	}
+)
+
+func init() {
+	versioningBumpCmd.PersistentFlags().StringVarP(&versionBumpPart, "part", "", "",
+		"The part of the version to increment. One of 'major', 'minor' or 'patch'.")
+	versioningBumpCmd.MarkPersistentFlagRequired("part")
+
+	versioningBumpCmd.PersistentFlags().StringVarP(&versionBumpSuffix, "suffix", "s", "",
+		"Optional. The version suffix to replace any existing suffix with. For example -SNAPSHOT"+
+			" Suffixes must start with '_' or '-'. Use \"\" to remove the suffix.")
+
+	versioningBumpCmd.PersistentFlags().BoolVarP(&versionBumpDryRun, "dry-run", "", false,
+		"Prints the old and new version of each module without changing any files.")
+
+	versioningBumpCmd.PersistentFlags().StringVarP(&versionBumpJournal, "journal", "j", "",
+		"Optional. The file to record the original contents of changed files in, so the changes can be undone"+
+			" with 'versioning undo'. Defaults to a file in a new temporary folder.")
+
+	addSourceFolderPathFlag(versioningBumpCmd)
+
+	versioningCmd.AddCommand(versioningBumpCmd)
+}
+
+func versioningBumpExecute(cmd *cobra.Command, args []string) {
+
+	isReplacingSuffix := cmd.Flags().Changed("suffix")
+
+	fs := utils.NewOSFileSystem()
+	err := versioning.BumpExecute(fs, sourceCodeFolderPath, versionBumpPart, isReplacingSuffix, versionBumpSuffix, versionBumpDryRun, versionBumpJournal)
+
+	if err != nil {
+		panic(err)
+	}
+
+}
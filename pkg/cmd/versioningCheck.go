@@ -0,0 +1,76 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"galasa.dev/buildUtilities/pkg/releases"
+	"galasa.dev/buildUtilities/pkg/utils"
+	"galasa.dev/buildUtilities/pkg/versioning"
+	"github.com/spf13/cobra"
+)
+
+var (
+	versionCheckSameSuffix  bool
+	versionCheckNoSnapshot  bool
+	versionCheckReleaseFile string
+
+	versioningCheckCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Checks the source module versions recursively against a version policy.",
+		Long: "Checks the source module versions recursively against a version policy." +
+			" Any modules which break the policy are reported, and the exit code is non-zero.",
+		Run: versioningCheckExecute,
+	}
+)
+
+func init() {
+	versioningCheckCmd.PersistentFlags().BoolVarP(&versionCheckSameSuffix, "same-suffix", "", false,
+		"All modules in the same folder must have the same version suffix.")
+	versioningCheckCmd.PersistentFlags().BoolVarP(&versionCheckNoSnapshot, "no-snapshot", "", false,
+		"No module may have a -SNAPSHOT version.")
+	versioningCheckCmd.PersistentFlags().StringVarP(&versionCheckReleaseFile, "release", "r", "",
+		"A release metadata file. No module may have a version lower than its version in this file.")
+
+	addSourceFolderPathFlag(versioningCheckCmd)
+
+	versioningCmd.AddCommand(versioningCheckCmd)
+}
+
+func versioningCheckExecute(cmd *cobra.Command, args []string) {
+	var exitCode = 0
+
+	fs := utils.NewOSFileSystem()
+
+	policy := versioning.CheckPolicy{
+		IsSameSuffixRequired: versionCheckSameSuffix,
+		IsSnapshotForbidden:  versionCheckNoSnapshot,
+	}
+
+	var err error
+	if versionCheckReleaseFile != "" {
+		var releasedMetadata galasayaml.Release
+		releasedMetadata, err = releases.ReadReleaseFile(fs, versionCheckReleaseFile)
+		if err == nil {
+			policy.MinimumVersions = releases.GetArtifactVersions(releasedMetadata)
+		}
+	}
+
+	if err == nil {
+		err = versioning.CheckExecute(fs, sourceCodeFolderPath, policy)
+	}
+
+	if err != nil {
+		exitCode = 1
+		fmt.Println(err.Error())
+	}
+
+	os.Exit(exitCode)
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package cmd
+
+import (
+	"errors"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"galasa.dev/buildUtilities/pkg/releases"
+	"galasa.dev/buildUtilities/pkg/utils"
+	"galasa.dev/buildUtilities/pkg/versioning"
+	"github.com/spf13/cobra"
+)
+
+var (
+	versionDiffFrom   string
+	versionDiffTo     string
+	versionDiffFormat string
+
+	versioningDiffCmd = &cobra.Command{
+		Use:   "diff",
+		Short: "Compares the source module versions against another source tree or a release metadata file.",
+		Long: "Compares the source module versions found in the --to folder against those in the --from" +
+			" source tree or release metadata file, and lists the modules which were added, removed, bumped or downgraded.",
+		Run: versioningDiffExecute,
+	}
+)
+
+func init() {
+	versioningDiffCmd.PersistentFlags().StringVarP(&versionDiffFrom, "from", "", "",
+		"The source tree folder, or release metadata file, holding the earlier module versions.")
+	versioningDiffCmd.MarkPersistentFlagRequired("from")
+
+	versioningDiffCmd.PersistentFlags().StringVarP(&versionDiffTo, "to", "", "",
+		"The source tree folder holding the later module versions. Defaults to the --sourcefolderpath folder.")
+
+	versioningDiffCmd.PersistentFlags().StringVarP(&versionDiffFormat, "format", "f", versioning.LIST_FORMAT_TEXT,
+		"The format of the differences. One of 'text' or 'json'.")
+
+	// Not mandatory here, as --to can be used instead.
+	versioningDiffCmd.PersistentFlags().StringVarP(&sourceCodeFolderPath, SOURCE_FOLDER_PATH, "p", "",
+		"Path to the source tree to compare, if --to is not used.")
+
+	versioningCmd.AddCommand(versioningDiffCmd)
+}
+
+func versioningDiffExecute(cmd *cobra.Command, args []string) {
+
+	fs := utils.NewOSFileSystem()
+
+	var fromVersions map[string]string
+	toFolderPath, err := getFolderToDiffTo(versionDiffTo, sourceCodeFolderPath)
+	if err == nil {
+		fromVersions, err = getVersionsToDiffFrom(fs, versionDiffFrom)
+	}
+	if err == nil {
+		err = versioning.DiffExecute(fs, fromVersions, toFolderPath, versionDiffFormat)
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+}
+
+// The versions to compare against come from a source tree if we are given a folder, or from
+// the bundles in a release metadata file otherwise.
+func getVersionsToDiffFrom(fs utils.FileSystem, fromPath string) (map[string]string, error) {
+	var fromVersions map[string]string
+
+	isFolder, err := fs.DirExists(fromPath)
+	if err == nil {
+		if isFolder {
+			fromVersions, err = versioning.GetModuleVersions(fs, fromPath)
+		} else {
+			var fromRelease galasayaml.Release
+			fromRelease, err = releases.ReadReleaseFile(fs, fromPath)
+			if err == nil {
+				fromVersions = releases.GetArtifactVersions(fromRelease)
+			}
+		}
+	}
+
+	return fromVersions, err
+}
+
+// The later versions come from the --to folder, or the --sourcefolderpath folder if there is no --to.
+func getFolderToDiffTo(toPath string, sourceFolderPath string) (string, error) {
+	var err error
+
+	folderPath := toPath
+	if folderPath == "" {
+		folderPath = sourceFolderPath
+	}
+	if folderPath == "" {
+		err = errors.New("The folder to compare is missing. Use --to or --sourcefolderpath.")
+	}
+
+	return folderPath, err
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createSourceTreeToDiff(fs utils.FileSystem, folder string, version string) {
+	fs.MkdirAll(folder + "/module1")
+	fs.WriteTextFile(folder+"/module1/build.gradle", `version = "`+version+`"`)
+	fs.WriteTextFile(folder+"/module1/settings.gradle", `rootProject.name = 'dev.galasa.module1'`)
+}
+
+func TestDiffComparesAgainstTheToFolder(t *testing.T) {
+	folderPath, err := getFolderToDiffTo("/new", "/source")
+	assert.Nil(t, err)
+	assert.Equal(t, "/new", folderPath)
+}
+
+func TestDiffDefaultsToTheSourceFolder(t *testing.T) {
+	folderPath, err := getFolderToDiffTo("", "/source")
+	assert.Nil(t, err)
+	assert.Equal(t, "/source", folderPath)
+}
+
+func TestDiffFailsWithNoFolderToCompare(t *testing.T) {
+	_, err := getFolderToDiffTo("", "")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "--to")
+}
+
+func TestDiffReadsVersionsFromTheFromFolder(t *testing.T) {
+
+	// Given...
+	mockFileSystem := utils.NewMockFileSystem()
+	createSourceTreeToDiff(mockFileSystem, "/old", "0.35.0")
+	createSourceTreeToDiff(mockFileSystem, "/new", "0.36.0")
+
+	// When...
+	fromVersions, err := getVersionsToDiffFrom(mockFileSystem, "/old")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"dev.galasa.module1": "0.35.0"}, fromVersions)
+}
@@ -13,6 +13,8 @@ import (
 )
 
 var (
+	versionListFormat string
+
 	versioningListCmd = &cobra.Command{
 		Use:   "list",
 		Short: "Lists the versions of all the modules in the source folder recursively.",
@@ -22,13 +24,18 @@ var (
 )
 
 func init() {
+	versioningListCmd.PersistentFlags().StringVarP(&versionListFormat, "format", "f", versioning.LIST_FORMAT_TEXT,
+		"The format of the listing. One of 'text', 'json', 'yaml' or 'csv'.")
+
+	addSourceFolderPathFlag(versioningListCmd)
+
 	versioningCmd.AddCommand(versioningListCmd)
 }
 
 func versioningListExecute(cmd *cobra.Command, args []string) {
 
 	fs := utils.NewOSFileSystem()
-	err := versioning.ListExecute(fs, sourceCodeFolderPath)
+	err := versioning.ListExecute(fs, sourceCodeFolderPath, versionListFormat)
 
 	if err != nil {
 		panic(err)
@@ -11,6 +11,8 @@ import (
 )
 
 var (
+	versionJournalFile string
+
 	versioningSuffixCmd = &cobra.Command{
 		Use:   "suffix",
 		Short: "Manipulates the suffix of source code recursively.",
@@ -19,5 +21,12 @@ var (
 )
 
 func init() {
+	versioningSuffixCmd.PersistentFlags().StringVarP(&versionJournalFile, "journal", "j", "",
+		"Optional. The file to record the original contents of changed files in, so the changes can be undone"+
+			" with 'versioning undo'. Defaults to a file in a new temporary folder.")
+
+	// For both 'suffix set' and 'suffix remove'
+	addSourceFolderPathFlag(versioningSuffixCmd)
+
 	versioningCmd.AddCommand(versioningSuffixCmd)
 }
@@ -28,7 +28,7 @@ func init() {
 func versioningSuffixRemoveExecute(cmd *cobra.Command, args []string) {
 
 	fs := utils.NewOSFileSystem()
-	err := versioning.SuffixRemoveExecute(fs, sourceCodeFolderPath)
+	err := versioning.SuffixRemoveExecute(fs, sourceCodeFolderPath, versionJournalFile)
 
 	if err != nil {
 		panic(err)
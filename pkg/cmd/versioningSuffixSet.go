@@ -36,7 +36,7 @@ func init() {
 func versioningSuffixSetExecute(cmd *cobra.Command, args []string) {
 
 	fs := utils.NewOSFileSystem()
-	err := versioning.SuffixSetExecute(fs, sourceCodeFolderPath, versionSuffix)
+	err := versioning.SuffixSetExecute(fs, sourceCodeFolderPath, versionSuffix, versionJournalFile)
 
 	if err != nil {
 		panic(err)
@@ -0,0 +1,44 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package cmd
+
+import (
+	"galasa.dev/buildUtilities/pkg/utils"
+	"galasa.dev/buildUtilities/pkg/versioning"
+	"github.com/spf13/cobra"
+)
+
+var (
+	versionUndoJournalFile string
+
+	versioningUndoCmd = &cobra.Command{
+		Use:   "undo",
+		Short: "Restores files changed by a versioning suffix or bump command from its journal.",
+		Long: "Restores files changed by a 'versioning suffix set', 'versioning suffix remove' or 'versioning bump' command" +
+			" to their original contents, using the journal file the command wrote.",
+		Run: versioningUndoExecute,
+	}
+)
+
+func init() {
+	versioningUndoCmd.PersistentFlags().StringVarP(&versionUndoJournalFile, "journal", "j", "",
+		"The journal file written by the versioning suffix or bump command.")
+	versioningUndoCmd.MarkPersistentFlagRequired("journal")
+
+	versioningCmd.AddCommand(versioningUndoCmd)
+}
+
+func versioningUndoExecute(cmd *cobra.Command, args []string) {
+
+	fs := utils.NewOSFileSystem()
+	err := versioning.UndoExecute(fs, versionUndoJournalFile)
+
+	if err != nil {
+		panic(err)
+	}
+
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package galasayaml
+
+import (
+	"fmt"
+	"strings"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+const (
+	// The bundle from the release merged last is kept.
+	MERGE_STRATEGY_LAST_WINS = "last-wins"
+	// The bundle with the highest version is kept. If the versions are the same, the last one is kept.
+	MERGE_STRATEGY_HIGHEST_VERSION = "highest-version"
+	// Two different bundles with the same group:artifact stop the merge.
+	MERGE_STRATEGY_ERROR = "error"
+)
+
+var MergeStrategies = []string{MERGE_STRATEGY_LAST_WINS, MERGE_STRATEGY_HIGHEST_VERSION, MERGE_STRATEGY_ERROR}
+
+// A group:artifact which was in more than one of the merged releases, with different details.
+type MergeConflict struct {
+	Key           string
+	OldSection    string
+	OldVersion    string
+	NewSection    string
+	NewVersion    string
+	ChosenVersion string
+}
+
+func (conflict MergeConflict) String() string {
+	return fmt.Sprintf("%v is %v in %v and %v in %v, using %v",
+		conflict.Key, conflict.OldVersion, conflict.OldSection, conflict.NewVersion, conflict.NewSection, conflict.ChosenVersion)
+}
+
+// MergeReleases combines several releases into one.
+//
+// The metadata of the first release is used, with the release version, name, apiVersion and kind
+// replaced by any later release which sets them. Bundles stay in the section they came from.
+// Where bundles with the same group:artifact are found, the strategy decides which is kept. The kept
+// bundle goes in the section of the release it was kept from. It stays at the position the group:artifact
+// was first seen, unless that was in a different section, when it is put where it was in the release it
+// was kept from. Identical duplicates are dropped quietly, other duplicates are returned as conflicts.
+func MergeReleases(releases []Release, strategy string) (Release, []MergeConflict, error) {
+	var merged Release
+	var conflicts []MergeConflict
+
+	err := validateMergeStrategy(strategy)
+	if err == nil {
+		var mergedBundles []sectionBundle
+		indexes := make(map[string]int)
+
+		for index, release := range releases {
+			if index == 0 {
+				merged.ApiVersion = release.ApiVersion
+				merged.Kind = release.Kind
+				merged.Metadata = release.Metadata
+				merged.Release = release.Release
+			} else {
+				mergeReleaseMetadata(&merged, release)
+			}
+
+			for _, sectionName := range SectionNames {
+				for _, bundle := range *release.GetSectionBundles(sectionName) {
+					var conflict *MergeConflict
+					conflict, err = mergeBundle(&mergedBundles, indexes, sectionBundle{section: sectionName, bundle: bundle}, strategy)
+					if err != nil {
+						break
+					}
+					if conflict != nil {
+						conflicts = append(conflicts, *conflict)
+					}
+				}
+				if err != nil {
+					break
+				}
+			}
+			if err != nil {
+				break
+			}
+		}
+
+		// The sections are only filled in once it is known which section each kept bundle belongs in.
+		for _, mergedBundle := range mergedBundles {
+			if !mergedBundle.isMoved {
+				bundles := merged.GetSectionBundles(mergedBundle.section)
+				*bundles = append(*bundles, mergedBundle.bundle)
+			}
+		}
+	}
+
+	return merged, conflicts, err
+}
+
+func validateMergeStrategy(strategy string) error {
+	var err error
+	isValid := false
+	for _, validStrategy := range MergeStrategies {
+		if strategy == validStrategy {
+			isValid = true
+		}
+	}
+	if !isValid {
+		err = fmt.Errorf("merge strategy '%s' is not valid, use one of %s", strategy, strings.Join(MergeStrategies, ", "))
+	}
+	return err
+}
+
+func mergeReleaseMetadata(merged *Release, release Release) {
+	if release.ApiVersion != "" {
+		merged.ApiVersion = release.ApiVersion
+	}
+	if release.Kind != "" {
+		merged.Kind = release.Kind
+	}
+	if release.Metadata.Name != "" {
+		merged.Metadata.Name = release.Metadata.Name
+	}
+	if release.Release.Version != "" {
+		merged.Release.Version = release.Release.Version
+	}
+}
+
+// A bundle and the section of the release it came from.
+type sectionBundle struct {
+	section string
+	bundle  Bundle
+
+	// The bundle was replaced by one from a different section, which is later in the merged bundles.
+	isMoved bool
+}
+
+// Adds a bundle to the merged bundles, or if its group:artifact is already there, uses the strategy to
+// decide which to keep. indexes holds where each group:artifact is in the merged bundles.
+func mergeBundle(mergedBundles *[]sectionBundle, indexes map[string]int, candidate sectionBundle, strategy string) (*MergeConflict, error) {
+	var err error
+	var conflict *MergeConflict
+
+	key := candidate.bundle.GetKey()
+	index, isDuplicate := indexes[key]
+	if !isDuplicate {
+		indexes[key] = len(*mergedBundles)
+		*mergedBundles = append(*mergedBundles, candidate)
+	} else {
+		existing := (*mergedBundles)[index]
+
+		if existing != candidate {
+			chosen := candidate
+			if strategy == MERGE_STRATEGY_HIGHEST_VERSION && utils.CompareVersions(existing.bundle.Version, candidate.bundle.Version) > 0 {
+				chosen = existing
+			}
+
+			conflict = &MergeConflict{
+				Key:           key,
+				OldSection:    existing.section,
+				OldVersion:    existing.bundle.Version,
+				NewSection:    candidate.section,
+				NewVersion:    candidate.bundle.Version,
+				ChosenVersion: chosen.bundle.Version,
+			}
+
+			if strategy == MERGE_STRATEGY_ERROR {
+				err = fmt.Errorf("conflicting bundles found while merging releases: %s is %s in %s and %s in %s",
+					key, existing.bundle.Version, existing.section, candidate.bundle.Version, candidate.section)
+			} else if chosen.section == existing.section {
+				(*mergedBundles)[index] = chosen
+			} else {
+				(*mergedBundles)[index].isMoved = true
+				indexes[key] = len(*mergedBundles)
+				*mergedBundles = append(*mergedBundles, chosen)
+			}
+		}
+	}
+
+	return conflict, err
+}
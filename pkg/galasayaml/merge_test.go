@@ -0,0 +1,143 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package galasayaml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createMergeTestReleases() []Release {
+	var first Release
+	first.ApiVersion = "galasa.dev/v1alpha"
+	first.Kind = "Release"
+	first.Metadata.Name = "framework"
+	first.Release.Version = "0.36.0"
+	first.Framework.Bundles = []Bundle{
+		{Artifact: "dev.galasa.framework", Version: "0.36.0", Obr: true},
+		{Artifact: "dev.galasa.shared", Version: "0.2.0"},
+	}
+	first.Api.Bundles = []Bundle{
+		{Artifact: "dev.galasa.api", Version: "0.36.0"},
+	}
+
+	var second Release
+	second.Metadata.Name = "managers"
+	second.Managers.Bundles = []Bundle{
+		{Artifact: "dev.galasa.zos.manager", Version: "0.36.0"},
+		{Group: "dev.galasa", Artifact: "dev.galasa.shared", Version: "0.1.0"},
+	}
+	second.External.Bundles = []Bundle{
+		{Group: "org.example", Artifact: "dev.galasa.framework", Version: "9.9.9"},
+		{Artifact: "dev.galasa.api", Version: "0.36.0"},
+	}
+	return []Release{first, second}
+}
+
+func TestMergeKeepsSections(t *testing.T) {
+	merged, _, err := MergeReleases(createMergeTestReleases(), MERGE_STRATEGY_LAST_WINS)
+	assert.Nil(t, err)
+
+	assert.Len(t, merged.Framework.Bundles, 1)
+	assert.Equal(t, "dev.galasa.framework", merged.Framework.Bundles[0].Artifact)
+	assert.Len(t, merged.Managers.Bundles, 2)
+	assert.Equal(t, "dev.galasa.zos.manager", merged.Managers.Bundles[0].Artifact)
+
+	// Same artifact name, but a different group, so not a duplicate.
+	assert.Len(t, merged.External.Bundles, 2)
+	assert.Equal(t, "org.example", merged.External.Bundles[0].Group)
+}
+
+func TestMergeTakesMetadataFromLaterReleasesWhenSet(t *testing.T) {
+	merged, _, err := MergeReleases(createMergeTestReleases(), MERGE_STRATEGY_LAST_WINS)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "galasa.dev/v1alpha", merged.ApiVersion)
+	assert.Equal(t, "Release", merged.Kind)
+	assert.Equal(t, "managers", merged.Metadata.Name)
+	assert.Equal(t, "0.36.0", merged.Release.Version)
+}
+
+func TestMergeLastWinsReplacesDuplicates(t *testing.T) {
+	merged, conflicts, err := MergeReleases(createMergeTestReleases(), MERGE_STRATEGY_LAST_WINS)
+	assert.Nil(t, err)
+
+	// The bundles which win are moved to the section of the release they came from.
+	assert.Equal(t, []Bundle{{Artifact: "dev.galasa.framework", Version: "0.36.0", Obr: true}}, merged.Framework.Bundles)
+	assert.Len(t, merged.Api.Bundles, 0)
+	assert.Equal(t, []Bundle{
+		{Artifact: "dev.galasa.zos.manager", Version: "0.36.0"},
+		{Group: "dev.galasa", Artifact: "dev.galasa.shared", Version: "0.1.0"},
+	}, merged.Managers.Bundles)
+	assert.Equal(t, "dev.galasa.api", merged.External.Bundles[1].Artifact)
+
+	assert.Len(t, conflicts, 2)
+	assert.Equal(t, MergeConflict{
+		Key:           "dev.galasa:dev.galasa.shared",
+		OldSection:    SECTION_FRAMEWORK,
+		OldVersion:    "0.2.0",
+		NewSection:    SECTION_MANAGERS,
+		NewVersion:    "0.1.0",
+		ChosenVersion: "0.1.0",
+	}, conflicts[0])
+	assert.Equal(t, "dev.galasa:dev.galasa.api", conflicts[1].Key)
+}
+
+func TestMergeHighestVersionKeepsHighestVersion(t *testing.T) {
+	merged, conflicts, err := MergeReleases(createMergeTestReleases(), MERGE_STRATEGY_HIGHEST_VERSION)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "0.2.0", merged.Framework.Bundles[1].Version)
+	assert.Equal(t, "0.2.0", conflicts[0].ChosenVersion)
+
+	// The higher version was in the first release, so stays in its section.
+	assert.Len(t, merged.Managers.Bundles, 1)
+	assert.Equal(t, "dev.galasa.zos.manager", merged.Managers.Bundles[0].Artifact)
+}
+
+func TestMergeHighestVersionMovesHigherVersionToItsSection(t *testing.T) {
+	var first Release
+	first.Framework.Bundles = []Bundle{
+		{Artifact: "dev.galasa.a", Version: "0.1.0"},
+		{Artifact: "dev.galasa.moving", Version: "0.1.0"},
+		{Artifact: "dev.galasa.b", Version: "0.1.0"},
+	}
+	var second Release
+	second.Managers.Bundles = []Bundle{
+		{Artifact: "dev.galasa.c", Version: "0.1.0"},
+		{Artifact: "dev.galasa.moving", Version: "0.2.0"},
+	}
+
+	merged, conflicts, err := MergeReleases([]Release{first, second}, MERGE_STRATEGY_HIGHEST_VERSION)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []Bundle{{Artifact: "dev.galasa.a", Version: "0.1.0"}, {Artifact: "dev.galasa.b", Version: "0.1.0"}}, merged.Framework.Bundles)
+	assert.Equal(t, []Bundle{{Artifact: "dev.galasa.c", Version: "0.1.0"}, {Artifact: "dev.galasa.moving", Version: "0.2.0"}}, merged.Managers.Bundles)
+	assert.Len(t, conflicts, 1)
+	assert.Equal(t, SECTION_MANAGERS, conflicts[0].NewSection)
+}
+
+func TestMergeErrorStrategyFailsOnConflict(t *testing.T) {
+	_, _, err := MergeReleases(createMergeTestReleases(), MERGE_STRATEGY_ERROR)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "dev.galasa:dev.galasa.shared")
+}
+
+func TestMergeDropsIdenticalDuplicatesQuietly(t *testing.T) {
+	first := createMergeTestReleases()[0]
+
+	merged, conflicts, err := MergeReleases([]Release{first, first}, MERGE_STRATEGY_ERROR)
+	assert.Nil(t, err)
+	assert.Len(t, conflicts, 0)
+	assert.Len(t, merged.Framework.Bundles, 2)
+}
+
+func TestMergeRejectsUnknownStrategy(t *testing.T) {
+	_, _, err := MergeReleases(createMergeTestReleases(), "first-wins")
+	assert.NotNil(t, err)
+}
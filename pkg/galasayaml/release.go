@@ -6,6 +6,19 @@
 
 package galasayaml
 
+const (
+	SECTION_FRAMEWORK = "framework"
+	SECTION_API       = "api"
+	SECTION_MANAGERS  = "managers"
+	SECTION_EXTERNAL  = "external"
+)
+
+// The group of any bundle which doesn't say what its group is.
+const DEFAULT_BUNDLE_GROUP = "dev.galasa"
+
+// The names of the sections of a release, in the order they appear in a release file.
+var SectionNames = []string{SECTION_FRAMEWORK, SECTION_API, SECTION_MANAGERS, SECTION_EXTERNAL}
+
 type Release struct {
 	ApiVersion string `yaml:"apiVersion"`
 	Kind       string
@@ -13,32 +26,63 @@ type Release struct {
 		Name string
 	}
 	Release struct {
-		Version string
-	}
+		Version string `yaml:"version,omitempty"`
+	} `yaml:"release,omitempty"`
 	Framework struct {
-		Bundles []Bundle
-	}
+		Bundles []Bundle `yaml:"bundles,omitempty"`
+	} `yaml:"framework,omitempty"`
 	Api struct {
-		Bundles []Bundle
-	}
+		Bundles []Bundle `yaml:"bundles,omitempty"`
+	} `yaml:"api,omitempty"`
 	Managers struct {
-		Bundles []Bundle
-	}
+		Bundles []Bundle `yaml:"bundles,omitempty"`
+	} `yaml:"managers,omitempty"`
 	External struct {
-		Bundles []Bundle
-	}
+		Bundles []Bundle `yaml:"bundles,omitempty"`
+	} `yaml:"external,omitempty"`
 }
 
 type Bundle struct {
-	Group        string
-	Artifact     string
-	Version      string
-	Type         string
-	Obr          bool
-	Bom          bool
-	Isolated     bool
-	Mvp          bool
-	Javadoc      bool
-	Managerdoc   bool
-	Codecoverage bool
+	Group        string `yaml:"group,omitempty"`
+	Artifact     string `yaml:"artifact"`
+	Version      string `yaml:"version"`
+	Type         string `yaml:"type,omitempty"`
+	Obr          bool   `yaml:"obr,omitempty"`
+	Bom          bool   `yaml:"bom,omitempty"`
+	Isolated     bool   `yaml:"isolated,omitempty"`
+	Mvp          bool   `yaml:"mvp,omitempty"`
+	Javadoc      bool   `yaml:"javadoc,omitempty"`
+	Managerdoc   bool   `yaml:"managerdoc,omitempty"`
+	Codecoverage bool   `yaml:"codecoverage,omitempty"`
+}
+
+// GetSectionBundles returns the bundles of the named section, so they can be read or changed.
+// nil is returned if the section name is not known.
+func (release *Release) GetSectionBundles(sectionName string) *[]Bundle {
+	var bundles *[]Bundle
+	switch sectionName {
+	case SECTION_FRAMEWORK:
+		bundles = &release.Framework.Bundles
+	case SECTION_API:
+		bundles = &release.Api.Bundles
+	case SECTION_MANAGERS:
+		bundles = &release.Managers.Bundles
+	case SECTION_EXTERNAL:
+		bundles = &release.External.Bundles
+	}
+	return bundles
+}
+
+// GetGroup returns the group of the bundle, or the default group if it doesn't have one.
+func (bundle Bundle) GetGroup() string {
+	group := bundle.Group
+	if group == "" {
+		group = DEFAULT_BUNDLE_GROUP
+	}
+	return group
+}
+
+// GetKey returns the group:artifact which identifies the bundle within a release.
+func (bundle Bundle) GetKey() string {
+	return bundle.GetGroup() + ":" + bundle.Artifact
 }
@@ -0,0 +1,212 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+const (
+	DIFF_FORMAT_MARKDOWN = "markdown"
+	DIFF_FORMAT_JSON     = "json"
+)
+
+// The differences between two releases, section by section.
+type ReleaseDiff struct {
+	OldVersion string        `json:"oldVersion"`
+	NewVersion string        `json:"newVersion"`
+	Sections   []SectionDiff `json:"sections"`
+}
+
+// The bundles which were added to, removed from, or changed version in one section of a release.
+// A bundle which moves to a different section is removed from one section and added to the other.
+type SectionDiff struct {
+	Section string              `json:"section"`
+	Added   []ReleaseBundle     `json:"added"`
+	Removed []ReleaseBundle     `json:"removed"`
+	Updated []ReleaseBundleDiff `json:"updated"`
+}
+
+type ReleaseBundle struct {
+	Group    string `json:"group"`
+	Artifact string `json:"artifact"`
+	Version  string `json:"version"`
+}
+
+type ReleaseBundleDiff struct {
+	Group      string `json:"group"`
+	Artifact   string `json:"artifact"`
+	OldVersion string `json:"oldVersion"`
+	NewVersion string `json:"newVersion"`
+}
+
+// HasChanges returns true if anything was added, removed or updated in the section.
+func (diff SectionDiff) HasChanges() bool {
+	return len(diff.Added)+len(diff.Removed)+len(diff.Updated) > 0
+}
+
+// DiffExecute compares two release metadata files, printing the bundles which were added, removed
+// or had their version changed in each section, as markdown or json.
+func DiffExecute(fs utils.FileSystem, oldReleaseFilePath string, newReleaseFilePath string, format string) error {
+	var err error
+	var oldRelease galasayaml.Release
+	var newRelease galasayaml.Release
+
+	if format != DIFF_FORMAT_MARKDOWN && format != DIFF_FORMAT_JSON {
+		err = fmt.Errorf("Invalid format '%s'. It must be one of '%s' or '%s'.", format, DIFF_FORMAT_MARKDOWN, DIFF_FORMAT_JSON)
+	}
+
+	if err == nil {
+		oldRelease, err = ReadReleaseFile(fs, oldReleaseFilePath)
+	}
+
+	if err == nil {
+		newRelease, err = ReadReleaseFile(fs, newReleaseFilePath)
+	}
+
+	if err == nil {
+		diff := DiffReleases(oldRelease, newRelease)
+		err = printReleaseDiff(os.Stdout, diff, format)
+	}
+
+	return err
+}
+
+// DiffReleases compares the bundles in each section of two releases.
+func DiffReleases(oldRelease galasayaml.Release, newRelease galasayaml.Release) ReleaseDiff {
+	diff := ReleaseDiff{
+		OldVersion: oldRelease.Release.Version,
+		NewVersion: newRelease.Release.Version,
+		Sections:   make([]SectionDiff, 0),
+	}
+
+	for _, sectionName := range galasayaml.SectionNames {
+		sectionDiff := diffSection(sectionName,
+			*oldRelease.GetSectionBundles(sectionName),
+			*newRelease.GetSectionBundles(sectionName))
+		diff.Sections = append(diff.Sections, sectionDiff)
+	}
+
+	return diff
+}
+
+func diffSection(sectionName string, oldBundles []galasayaml.Bundle, newBundles []galasayaml.Bundle) SectionDiff {
+	diff := SectionDiff{
+		Section: sectionName,
+		Added:   make([]ReleaseBundle, 0),
+		Removed: make([]ReleaseBundle, 0),
+		Updated: make([]ReleaseBundleDiff, 0),
+	}
+
+	oldBundlesByKey := getBundlesByKey(oldBundles)
+	newBundlesByKey := getBundlesByKey(newBundles)
+
+	for _, key := range getSortedBundleKeys(newBundlesByKey) {
+		newBundle := newBundlesByKey[key]
+		oldBundle, isFound := oldBundlesByKey[key]
+
+		if !isFound {
+			diff.Added = append(diff.Added, newReleaseBundle(newBundle))
+		} else if oldBundle.Version != newBundle.Version {
+			diff.Updated = append(diff.Updated, ReleaseBundleDiff{
+				Group:      newBundle.GetGroup(),
+				Artifact:   newBundle.Artifact,
+				OldVersion: oldBundle.Version,
+				NewVersion: newBundle.Version,
+			})
+		}
+	}
+
+	for _, key := range getSortedBundleKeys(oldBundlesByKey) {
+		if _, isFound := newBundlesByKey[key]; !isFound {
+			diff.Removed = append(diff.Removed, newReleaseBundle(oldBundlesByKey[key]))
+		}
+	}
+
+	return diff
+}
+
+func newReleaseBundle(bundle galasayaml.Bundle) ReleaseBundle {
+	return ReleaseBundle{Group: bundle.GetGroup(), Artifact: bundle.Artifact, Version: bundle.Version}
+}
+
+func getBundlesByKey(bundles []galasayaml.Bundle) map[string]galasayaml.Bundle {
+	bundlesByKey := make(map[string]galasayaml.Bundle)
+	for _, bundle := range bundles {
+		bundlesByKey[bundle.GetKey()] = bundle
+	}
+	return bundlesByKey
+}
+
+func getSortedBundleKeys(bundlesByKey map[string]galasayaml.Bundle) []string {
+	keys := make([]string, 0, len(bundlesByKey))
+	for key := range bundlesByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func printReleaseDiff(writer io.Writer, diff ReleaseDiff, format string) error {
+	var err error
+
+	if format == DIFF_FORMAT_JSON {
+		var bytes []byte
+		bytes, err = json.MarshalIndent(diff, "", "  ")
+		if err == nil {
+			fmt.Fprintln(writer, string(bytes))
+		}
+	} else {
+		printReleaseDiffAsMarkdown(writer, diff)
+	}
+
+	return err
+}
+
+func printReleaseDiffAsMarkdown(writer io.Writer, diff ReleaseDiff) {
+	fmt.Fprintf(writer, "# Changes from %s to %s\n", diff.OldVersion, diff.NewVersion)
+
+	hasChanges := false
+	for _, section := range diff.Sections {
+		if section.HasChanges() {
+			hasChanges = true
+			fmt.Fprintf(writer, "\n## %s\n", section.Section)
+
+			if len(section.Added) > 0 {
+				fmt.Fprintf(writer, "\n### Added\n\n")
+				for _, bundle := range section.Added {
+					fmt.Fprintf(writer, "- `%s:%s` %s\n", bundle.Group, bundle.Artifact, bundle.Version)
+				}
+			}
+
+			if len(section.Removed) > 0 {
+				fmt.Fprintf(writer, "\n### Removed\n\n")
+				for _, bundle := range section.Removed {
+					fmt.Fprintf(writer, "- `%s:%s` %s\n", bundle.Group, bundle.Artifact, bundle.Version)
+				}
+			}
+
+			if len(section.Updated) > 0 {
+				fmt.Fprintf(writer, "\n### Updated\n\n")
+				for _, bundle := range section.Updated {
+					fmt.Fprintf(writer, "- `%s:%s` %s -> %s\n", bundle.Group, bundle.Artifact, bundle.OldVersion, bundle.NewVersion)
+				}
+			}
+		}
+	}
+
+	if !hasChanges {
+		fmt.Fprintf(writer, "\nNo bundles were changed.\n")
+	}
+}
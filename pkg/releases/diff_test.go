@@ -0,0 +1,102 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func createReleasesToDiff() (galasayaml.Release, galasayaml.Release) {
+	oldRelease := NewRelease()
+	oldRelease.Release.Version = "0.30.0"
+	oldRelease.Framework.Bundles = []galasayaml.Bundle{
+		{Artifact: "dev.galasa.framework", Version: "0.30.0"},
+		{Artifact: "dev.galasa.old", Version: "0.1.0"},
+	}
+	oldRelease.Managers.Bundles = []galasayaml.Bundle{
+		{Artifact: "dev.galasa.zos.manager", Version: "0.30.0"},
+	}
+
+	newRelease := NewRelease()
+	newRelease.Release.Version = "0.31.0"
+	newRelease.Framework.Bundles = []galasayaml.Bundle{
+		{Artifact: "dev.galasa.new", Version: "0.1.0"},
+		{Group: "dev.galasa", Artifact: "dev.galasa.framework", Version: "0.31.0"},
+	}
+	newRelease.Managers.Bundles = []galasayaml.Bundle{
+		{Artifact: "dev.galasa.zos.manager", Version: "0.30.0", Obr: true},
+	}
+	return oldRelease, newRelease
+}
+
+func TestCanDiffReleasesBySection(t *testing.T) {
+	oldRelease, newRelease := createReleasesToDiff()
+
+	diff := DiffReleases(oldRelease, newRelease)
+
+	assert.Equal(t, "0.30.0", diff.OldVersion)
+	assert.Equal(t, "0.31.0", diff.NewVersion)
+	assert.Len(t, diff.Sections, 4)
+
+	framework := diff.Sections[0]
+	assert.Equal(t, galasayaml.SECTION_FRAMEWORK, framework.Section)
+	assert.Equal(t, []ReleaseBundle{{Group: "dev.galasa", Artifact: "dev.galasa.new", Version: "0.1.0"}}, framework.Added)
+	assert.Equal(t, []ReleaseBundle{{Group: "dev.galasa", Artifact: "dev.galasa.old", Version: "0.1.0"}}, framework.Removed)
+	assert.Equal(t, []ReleaseBundleDiff{{
+		Group: "dev.galasa", Artifact: "dev.galasa.framework", OldVersion: "0.30.0", NewVersion: "0.31.0",
+	}}, framework.Updated)
+
+	// Only the flags changed, which doesn't count.
+	assert.False(t, diff.Sections[2].HasChanges())
+}
+
+func TestCanPrintReleaseDiffAsMarkdown(t *testing.T) {
+	oldRelease, newRelease := createReleasesToDiff()
+	var output bytes.Buffer
+
+	err := printReleaseDiff(&output, DiffReleases(oldRelease, newRelease), DIFF_FORMAT_MARKDOWN)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "# Changes from 0.30.0 to 0.31.0\n"+
+		"\n## framework\n"+
+		"\n### Added\n\n- `dev.galasa:dev.galasa.new` 0.1.0\n"+
+		"\n### Removed\n\n- `dev.galasa:dev.galasa.old` 0.1.0\n"+
+		"\n### Updated\n\n- `dev.galasa:dev.galasa.framework` 0.30.0 -> 0.31.0\n", output.String())
+}
+
+func TestMarkdownDiffSaysWhenNothingChanged(t *testing.T) {
+	oldRelease, _ := createReleasesToDiff()
+	var output bytes.Buffer
+
+	printReleaseDiff(&output, DiffReleases(oldRelease, oldRelease), DIFF_FORMAT_MARKDOWN)
+
+	assert.Equal(t, "# Changes from 0.30.0 to 0.30.0\n\nNo bundles were changed.\n", output.String())
+}
+
+func TestCanPrintReleaseDiffAsJson(t *testing.T) {
+	oldRelease, newRelease := createReleasesToDiff()
+	var output bytes.Buffer
+
+	err := printReleaseDiff(&output, DiffReleases(oldRelease, newRelease), DIFF_FORMAT_JSON)
+	assert.Nil(t, err)
+
+	var diff ReleaseDiff
+	err = json.Unmarshal(output.Bytes(), &diff)
+	assert.Nil(t, err)
+	assert.Equal(t, DiffReleases(oldRelease, newRelease), diff)
+	assert.Contains(t, output.String(), `"oldVersion": "0.30.0"`)
+}
+
+func TestReleaseDiffRejectsUnknownFormat(t *testing.T) {
+	err := DiffExecute(nil, "old.yaml", "new.yaml", "html")
+	assert.NotNil(t, err)
+}
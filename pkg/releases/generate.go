@@ -0,0 +1,226 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"galasa.dev/buildUtilities/pkg/utils"
+	"galasa.dev/buildUtilities/pkg/versioning"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DEFAULT_API_VERSION  = "galasa.dev/v1alpha"
+	DEFAULT_KIND         = "Release"
+	DEFAULT_RELEASE_NAME = "galasa-release"
+)
+
+// The bundles which were added, removed or had their version changed when generating a release.
+type GenerateChanges struct {
+	Added   []galasayaml.Bundle
+	Removed []galasayaml.Bundle
+	Updated []BundleVersionChange
+}
+
+type BundleVersionChange struct {
+	Artifact   string
+	OldVersion string
+	NewVersion string
+}
+
+// GenerateExecute builds release metadata from the modules found in the source code folder,
+// and writes it to the output file.
+//
+// If a previous release file is given, the metadata and bundle flags from it are kept. Bundles
+// in the previous file which are not found in the source code are removed, except for those in
+// the external section, which are never built from source. Modules which are not in the previous
+// release are added to the newBundleSection.
+//
+// The release version is taken from the previous release file, unless a release version is given.
+// One of them must supply a version, as the release metadata is of no use to templates without one.
+func GenerateExecute(
+	fs utils.FileSystem,
+	sourceCodeFolderPath string,
+	previousReleaseFilePath string,
+	releaseVersion string,
+	outputFilePath string,
+	newBundleSection string,
+) error {
+	var err error
+	var previousRelease galasayaml.Release
+
+	err = validateNewBundleSection(newBundleSection)
+	if err == nil {
+		if previousReleaseFilePath != "" {
+			previousRelease, err = ReadReleaseFile(fs, previousReleaseFilePath)
+		} else {
+			previousRelease = NewRelease()
+		}
+	}
+
+	if err == nil {
+		if releaseVersion != "" {
+			previousRelease.Release.Version = releaseVersion
+		} else if previousRelease.Release.Version == "" {
+			err = errors.New("The release has no version. Use --release-version, or --previous with a release file which has a version.")
+		}
+	}
+
+	if err == nil {
+		var modules []versioning.Module
+		modules, err = versioning.GetModules(fs, sourceCodeFolderPath)
+		if err == nil {
+			generatedRelease, changes := generateRelease(modules, previousRelease, newBundleSection)
+			printGenerateChanges(os.Stdout, changes)
+			err = WriteReleaseFile(fs, outputFilePath, generatedRelease)
+		}
+	}
+
+	return err
+}
+
+func validateNewBundleSection(sectionName string) error {
+	var err error
+	switch sectionName {
+	case galasayaml.SECTION_FRAMEWORK, galasayaml.SECTION_API, galasayaml.SECTION_MANAGERS:
+		// It's valid.
+	default:
+		err = fmt.Errorf("Invalid section '%s'. New bundles can only be added to the '%s', '%s' or '%s' sections.",
+			sectionName, galasayaml.SECTION_FRAMEWORK, galasayaml.SECTION_API, galasayaml.SECTION_MANAGERS)
+	}
+	return err
+}
+
+// NewRelease creates empty release metadata with the usual header fields filled in.
+func NewRelease() galasayaml.Release {
+	var release galasayaml.Release
+	release.ApiVersion = DEFAULT_API_VERSION
+	release.Kind = DEFAULT_KIND
+	release.Metadata.Name = DEFAULT_RELEASE_NAME
+	return release
+}
+
+// ReadReleaseFile reads release metadata from a yaml file.
+func ReadReleaseFile(fs utils.FileSystem, releaseFilePath string) (galasayaml.Release, error) {
+	var release galasayaml.Release
+
+	contents, err := fs.ReadTextFile(releaseFilePath)
+	if err != nil {
+		err = fmt.Errorf("failed to read release file %s - %s", releaseFilePath, err.Error())
+	} else {
+		err = yaml.Unmarshal([]byte(contents), &release)
+		if err != nil {
+			err = fmt.Errorf("failed to parse release file %s - %s", releaseFilePath, err.Error())
+		}
+	}
+
+	return release, err
+}
+
+// WriteReleaseFile writes release metadata to a yaml file.
+func WriteReleaseFile(fs utils.FileSystem, releaseFilePath string, release galasayaml.Release) error {
+	var buffer bytes.Buffer
+
+	// Indent the same way as our hand-written release files.
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+
+	err := encoder.Encode(release)
+	if err == nil {
+		err = encoder.Close()
+	}
+
+	if err == nil {
+		err = fs.WriteTextFile(releaseFilePath, buffer.String())
+	}
+	return err
+}
+
+func generateRelease(modules []versioning.Module, previousRelease galasayaml.Release, newBundleSection string) (galasayaml.Release, GenerateChanges) {
+	var changes GenerateChanges
+
+	moduleVersions := make(map[string]string)
+	for _, module := range modules {
+		moduleVersions[module.GetArtifactName()] = module.GetVersion()
+	}
+
+	generatedRelease := previousRelease
+	knownArtifacts := make(map[string]bool)
+
+	for _, sectionName := range galasayaml.SectionNames {
+		previousBundles := *previousRelease.GetSectionBundles(sectionName)
+		var generatedBundles []galasayaml.Bundle
+
+		for _, bundle := range previousBundles {
+			knownArtifacts[bundle.Artifact] = true
+
+			if sectionName == galasayaml.SECTION_EXTERNAL {
+				// External bundles are not built from source, so are kept as they are.
+				generatedBundles = append(generatedBundles, bundle)
+			} else {
+				version, isFound := moduleVersions[bundle.Artifact]
+				if !isFound {
+					changes.Removed = append(changes.Removed, bundle)
+				} else {
+					if version != bundle.Version {
+						changes.Updated = append(changes.Updated, BundleVersionChange{
+							Artifact:   bundle.Artifact,
+							OldVersion: bundle.Version,
+							NewVersion: version,
+						})
+						bundle.Version = version
+					}
+					generatedBundles = append(generatedBundles, bundle)
+				}
+			}
+		}
+
+		*generatedRelease.GetSectionBundles(sectionName) = generatedBundles
+	}
+
+	// Anything left over is new. The modules are already sorted, so the new bundles will be too.
+	newBundles := generatedRelease.GetSectionBundles(newBundleSection)
+	for _, module := range modules {
+		if !knownArtifacts[module.GetArtifactName()] {
+			bundle := galasayaml.Bundle{Artifact: module.GetArtifactName(), Version: module.GetVersion()}
+			*newBundles = append(*newBundles, bundle)
+			changes.Added = append(changes.Added, bundle)
+		}
+	}
+
+	return generatedRelease, changes
+}
+
+func printGenerateChanges(writer io.Writer, changes GenerateChanges) {
+	for _, bundle := range changes.Added {
+		fmt.Fprintf(writer, "Added bundle %s %s\n", bundle.Artifact, bundle.Version)
+	}
+	for _, bundle := range changes.Removed {
+		fmt.Fprintf(writer, "Removed bundle %s %s\n", bundle.Artifact, bundle.Version)
+	}
+	for _, change := range changes.Updated {
+		fmt.Fprintf(writer, "Updated bundle %s %s -> %s\n", change.Artifact, change.OldVersion, change.NewVersion)
+	}
+	fmt.Fprintf(writer, "%d added, %d removed, %d updated\n", len(changes.Added), len(changes.Removed), len(changes.Updated))
+}
+
+// GetArtifactVersions returns the version of every bundle in the release, keyed by artifact name.
+func GetArtifactVersions(release galasayaml.Release) map[string]string {
+	artifactVersions := make(map[string]string)
+	for _, sectionName := range galasayaml.SectionNames {
+		for _, bundle := range *release.GetSectionBundles(sectionName) {
+			artifactVersions[bundle.Artifact] = bundle.Version
+		}
+	}
+	return artifactVersions
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"galasa.dev/buildUtilities/pkg/utils"
+	"galasa.dev/buildUtilities/pkg/versioning"
+	"github.com/stretchr/testify/assert"
+)
+
+func createSourceTreeFs() *utils.MockFileSystem {
+	fs := utils.NewOverridableMockFileSystem()
+
+	fs.MkdirAll("/src/framework")
+	fs.WriteTextFile("/src/framework/build.gradle", `version = "0.31.0"`)
+	fs.WriteTextFile("/src/framework/settings.gradle", `rootProject.name = 'dev.galasa.framework'`)
+
+	fs.MkdirAll("/src/ras")
+	fs.WriteTextFile("/src/ras/build.gradle", `version = "0.16.0"`)
+	fs.WriteTextFile("/src/ras/settings.gradle", `rootProject.name = 'dev.galasa.framework.api.ras'`)
+
+	fs.MkdirAll("/src/newone")
+	fs.WriteTextFile("/src/newone/build.gradle", `version = "0.1.0"`)
+	fs.WriteTextFile("/src/newone/settings.gradle", `rootProject.name = 'dev.galasa.newone'`)
+	return fs
+}
+
+const previousReleaseYaml = `apiVersion: galasa.dev/v1alpha
+kind: Release
+metadata:
+  name: my-release
+release:
+  version: 0.31.0
+framework:
+  bundles:
+  - artifact: dev.galasa.framework
+    version: 0.30.0
+    bom: true
+    mvp: true
+    javadoc: true
+  - artifact: dev.galasa.gone
+    version: 0.30.0
+    obr: true
+api:
+  bundles:
+  - artifact: dev.galasa.framework.api.ras
+    version: 0.16.0
+    bom: true
+external:
+  bundles:
+  - group: commons-io
+    artifact: commons-io
+    version: 2.6
+    isolated: true
+`
+
+func TestGenerateFailsIfSectionIsInvalid(t *testing.T) {
+	err := GenerateExecute(nil, "/src", "", "0.31.0", "/out.yaml", galasayaml.SECTION_EXTERNAL)
+	assert.NotNil(t, err)
+}
+
+func TestCanGenerateReleaseWithNoPreviousRelease(t *testing.T) {
+	fs := createSourceTreeFs()
+
+	err := GenerateExecute(fs, "/src", "", "0.31.0", "/out/release.yaml", galasayaml.SECTION_MANAGERS)
+	assert.Nil(t, err)
+
+	release, err := ReadReleaseFile(fs, "/out/release.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "galasa.dev/v1alpha", release.ApiVersion)
+	assert.Equal(t, "Release", release.Kind)
+	assert.Equal(t, "galasa-release", release.Metadata.Name)
+	assert.Equal(t, "0.31.0", release.Release.Version)
+	assert.Len(t, release.Framework.Bundles, 0)
+	assert.Len(t, release.Managers.Bundles, 3)
+	assert.Equal(t, galasayaml.Bundle{Artifact: "dev.galasa.framework", Version: "0.31.0"}, release.Managers.Bundles[0])
+}
+
+func TestGenerateFailsIfThereIsNoReleaseVersion(t *testing.T) {
+	fs := createSourceTreeFs()
+
+	err := GenerateExecute(fs, "/src", "", "", "/out/release.yaml", galasayaml.SECTION_MANAGERS)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "--release-version")
+
+	isWritten, _ := fs.Exists("/out/release.yaml")
+	assert.False(t, isWritten)
+}
+
+func TestGenerateReleaseVersionReplacesThePreviousVersion(t *testing.T) {
+	fs := createSourceTreeFs()
+	fs.WriteTextFile("/release.yaml", previousReleaseYaml)
+
+	err := GenerateExecute(fs, "/src", "/release.yaml", "0.32.0", "/release.yaml", galasayaml.SECTION_FRAMEWORK)
+	assert.Nil(t, err)
+
+	release, _ := ReadReleaseFile(fs, "/release.yaml")
+	assert.Equal(t, "0.32.0", release.Release.Version)
+}
+
+func TestCanGenerateReleaseMergingWithPreviousRelease(t *testing.T) {
+	fs := createSourceTreeFs()
+	fs.WriteTextFile("/release.yaml", previousReleaseYaml)
+
+	err := GenerateExecute(fs, "/src", "/release.yaml", "", "/release.yaml", galasayaml.SECTION_FRAMEWORK)
+	assert.Nil(t, err)
+
+	release, err := ReadReleaseFile(fs, "/release.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-release", release.Metadata.Name)
+	assert.Equal(t, "0.31.0", release.Release.Version)
+
+	// Flags are kept, versions come from the source, removed bundles go and new ones are added.
+	assert.Equal(t, []galasayaml.Bundle{
+		{Artifact: "dev.galasa.framework", Version: "0.31.0", Bom: true, Mvp: true, Javadoc: true},
+		{Artifact: "dev.galasa.newone", Version: "0.1.0"},
+	}, release.Framework.Bundles)
+
+	assert.Equal(t, []galasayaml.Bundle{
+		{Artifact: "dev.galasa.framework.api.ras", Version: "0.16.0", Bom: true},
+	}, release.Api.Bundles)
+
+	// External bundles are never built from source, so are kept.
+	assert.Equal(t, []galasayaml.Bundle{
+		{Group: "commons-io", Artifact: "commons-io", Version: "2.6", Isolated: true},
+	}, release.External.Bundles)
+}
+
+func TestGenerateReportsChanges(t *testing.T) {
+	fs := createSourceTreeFs()
+	fs.WriteTextFile("/release.yaml", previousReleaseYaml)
+	previousRelease, _ := ReadReleaseFile(fs, "/release.yaml")
+	modules, _ := versioning.GetModules(fs, "/src")
+
+	_, changes := generateRelease(modules, previousRelease, galasayaml.SECTION_FRAMEWORK)
+
+	assert.Len(t, changes.Added, 1)
+	assert.Equal(t, "dev.galasa.newone", changes.Added[0].Artifact)
+	assert.Len(t, changes.Removed, 1)
+	assert.Equal(t, "dev.galasa.gone", changes.Removed[0].Artifact)
+	assert.Equal(t, []BundleVersionChange{
+		{Artifact: "dev.galasa.framework", OldVersion: "0.30.0", NewVersion: "0.31.0"},
+	}, changes.Updated)
+}
+
+func TestGenerateMatchesSubprojectsByArtifactName(t *testing.T) {
+	fs := createSourceTreeFs()
+	fs.MkdirAll("/src/managers")
+	fs.WriteTextFile("/src/managers/settings.gradle", `rootProject.name = 'galasa-managers-parent'
+include 'dev.galasa.foo.manager'
+`)
+	fs.WriteTextFile("/src/managers/build.gradle", `// No version here`)
+	fs.MkdirAll("/src/managers/dev.galasa.foo.manager")
+	fs.WriteTextFile("/src/managers/dev.galasa.foo.manager/build.gradle", `version = '0.31.0'`)
+
+	fs.WriteTextFile("/release.yaml", previousReleaseYaml+`managers:
+  bundles:
+  - artifact: dev.galasa.foo.manager
+    version: 0.30.0
+    obr: true
+`)
+
+	err := GenerateExecute(fs, "/src", "/release.yaml", "", "/release.yaml", galasayaml.SECTION_FRAMEWORK)
+	assert.Nil(t, err)
+
+	release, err := ReadReleaseFile(fs, "/release.yaml")
+	assert.Nil(t, err)
+
+	// The subproject's bundle is updated, rather than removed and added again under its qualified name.
+	assert.Equal(t, []galasayaml.Bundle{
+		{Artifact: "dev.galasa.foo.manager", Version: "0.31.0", Obr: true},
+	}, release.Managers.Bundles)
+	for _, bundle := range release.Framework.Bundles {
+		assert.NotContains(t, bundle.Artifact, ":")
+	}
+}
+
+func TestCanGetArtifactVersionsFromAllSections(t *testing.T) {
+	fs := createSourceTreeFs()
+	fs.WriteTextFile("/release.yaml", previousReleaseYaml)
+	release, _ := ReadReleaseFile(fs, "/release.yaml")
+
+	artifactVersions := GetArtifactVersions(release)
+
+	assert.Equal(t, map[string]string{
+		"dev.galasa.framework":         "0.30.0",
+		"dev.galasa.gone":              "0.30.0",
+		"dev.galasa.framework.api.ras": "0.16.0",
+		"commons-io":                   "2.6",
+	}, artifactVersions)
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/template"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"galasa.dev/buildUtilities/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// One output to render from a template manifest.
+//
+// The artifacts are picked using either a selector expression, or the name of one of the SelectorPresets.
+type TemplateManifestEntry struct {
+	Template string `yaml:"template"`
+	Output   string `yaml:"output"`
+	Selector string `yaml:"selector,omitempty"`
+	Preset   string `yaml:"preset,omitempty"`
+}
+
+// ReadTemplateManifest reads a yaml list of template manifest entries. Relative template and output
+// paths are relative to the folder the manifest is in, so the manifest works from any current folder.
+func ReadTemplateManifest(fs utils.FileSystem, manifestFilePath string) ([]TemplateManifestEntry, error) {
+	var entries []TemplateManifestEntry
+
+	contents, err := fs.ReadTextFile(manifestFilePath)
+	if err == nil {
+		decoder := yaml.NewDecoder(bytes.NewReader([]byte(contents)))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&entries)
+		if err != nil {
+			err = fmt.Errorf("failed to read template manifest %s - %s", manifestFilePath, err.Error())
+		}
+	}
+
+	for index := 0; err == nil && index < len(entries); index++ {
+		err = validateTemplateManifestEntry(entries[index], index+1)
+	}
+
+	if err == nil {
+		manifestFolderPath := filepath.Dir(manifestFilePath)
+		for index := range entries {
+			entries[index].Template = resolveManifestPath(manifestFolderPath, entries[index].Template)
+			if entries[index].Output != STDOUT_OUTPUT_FILE_PATH {
+				entries[index].Output = resolveManifestPath(manifestFolderPath, entries[index].Output)
+			}
+		}
+	}
+
+	return entries, err
+}
+
+func resolveManifestPath(manifestFolderPath string, filePath string) string {
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(manifestFolderPath, filePath)
+	}
+	return filePath
+}
+
+func validateTemplateManifestEntry(entry TemplateManifestEntry, entryNumber int) error {
+	var err error
+	if entry.Template == "" {
+		err = fmt.Errorf("template manifest entry %d has no template", entryNumber)
+	} else if entry.Output == "" {
+		err = fmt.Errorf("template manifest entry %d has no output", entryNumber)
+	} else if (entry.Selector == "") == (entry.Preset == "") {
+		err = fmt.Errorf("template manifest entry %d must have either a selector or a preset", entryNumber)
+	}
+	return err
+}
+
+// GetSelectorExpression returns the selector expression the entry uses, looking up its preset if it has one.
+func (entry TemplateManifestEntry) GetSelectorExpression() (string, error) {
+	var err error
+	expression := entry.Selector
+	if entry.Preset != "" {
+		var isFound bool
+		expression, isFound = SelectorPresets[entry.Preset]
+		if !isFound {
+			err = fmt.Errorf("unknown selector preset '%s'", entry.Preset)
+		}
+	}
+	return expression, err
+}
+
+// RenderTemplate executes a template against the artifacts of the release which the selector picks.
+// The partials are other templates, keyed by name, which the template can include.
+func RenderTemplate(
+	templateName string,
+	templateText string,
+	partials map[string]string,
+	release galasayaml.Release,
+	selector Selector,
+) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	tmpl, err := template.New(templateName).Funcs(GetTemplateFunctions()).Parse(templateText)
+	for _, partialName := range getSortedPartialNames(partials) {
+		if err != nil {
+			break
+		}
+		_, err = tmpl.New(partialName).Parse(partials[partialName])
+	}
+
+	if err == nil {
+		data := NewTemplateData(release, SelectBundles(&release, selector))
+		err = tmpl.Execute(&buffer, data)
+	}
+
+	return buffer.Bytes(), err
+}
+
+// A rendered template, and the file it should be written to.
+type RenderedOutput struct {
+	TemplateFilePath string
+	OutputFilePath   string
+	Contents         []byte
+}
+
+// renderManifestOutputs renders every entry of a template manifest against the release.
+//
+// All the templates are rendered before any output is written, so if any template fails then
+// none of the output files are changed.
+func renderManifestOutputs(fs utils.FileSystem, entries []TemplateManifestEntry, partials map[string]string, release galasayaml.Release) ([]RenderedOutput, error) {
+	var err error
+	outputs := make([]RenderedOutput, len(entries))
+
+	for index, entry := range entries {
+		outputs[index] = RenderedOutput{TemplateFilePath: entry.Template, OutputFilePath: entry.Output}
+		outputs[index].Contents, err = renderManifestEntry(fs, entry, partials, release)
+		if err != nil {
+			err = fmt.Errorf("failed to render %s from %s, no output files have been written - %s",
+				entry.Output, entry.Template, err.Error())
+			break
+		}
+	}
+
+	return outputs, err
+}
+
+// writeRenderedOutputs writes each rendered template to its output file, or to stdout if the
+// output file is '-'. The files are written one at a time, so if one can't be written then the
+// ones before it have already been changed.
+func writeRenderedOutputs(fs utils.FileSystem, outputs []RenderedOutput, stdout io.Writer, progress io.Writer) error {
+	var err error
+
+	for _, output := range outputs {
+		if output.OutputFilePath == STDOUT_OUTPUT_FILE_PATH {
+			_, err = stdout.Write(output.Contents)
+		} else {
+			err = fs.WriteBinaryFile(output.OutputFilePath, output.Contents)
+		}
+
+		if err != nil {
+			err = fmt.Errorf("failed to write output file %s - %s", output.OutputFilePath, err.Error())
+			break
+		}
+		fmt.Fprintf(progress, "Rendered %v from %v\n", output.OutputFilePath, output.TemplateFilePath)
+	}
+
+	return err
+}
+
+// checkRenderedOutputs compares each rendered template with its existing output file, printing
+// a unified diff of any which are different. An error is returned if any are different.
+func checkRenderedOutputs(fs utils.FileSystem, outputs []RenderedOutput, stdout io.Writer, progress io.Writer) error {
+	var err error
+	driftCount := 0
+
+	for _, output := range outputs {
+		if output.OutputFilePath == STDOUT_OUTPUT_FILE_PATH {
+			err = errors.New("the output '-' cannot be checked, as it is not a file")
+			break
+		}
+
+		existingContents := ""
+		var isExisting bool
+		isExisting, err = fs.Exists(output.OutputFilePath)
+		if err == nil && isExisting {
+			existingContents, err = fs.ReadTextFile(output.OutputFilePath)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to read output file %s - %s", output.OutputFilePath, err.Error())
+			break
+		}
+
+		diff := utils.UnifiedDiff(output.OutputFilePath,
+			output.OutputFilePath+" (rendered from "+output.TemplateFilePath+")",
+			existingContents, string(output.Contents))
+		if diff != "" {
+			driftCount++
+			fmt.Fprint(stdout, diff)
+		}
+	}
+
+	if err == nil {
+		if driftCount > 0 {
+			err = fmt.Errorf("%d of %d output files are out of date with the release metadata", driftCount, len(outputs))
+		} else {
+			fmt.Fprintf(progress, "%d output files are up to date\n", len(outputs))
+		}
+	}
+
+	return err
+}
+
+func renderManifestEntry(fs utils.FileSystem, entry TemplateManifestEntry, partials map[string]string, release galasayaml.Release) ([]byte, error) {
+	var output []byte
+	var selector Selector
+
+	expression, err := entry.GetSelectorExpression()
+	if err == nil {
+		selector, err = ParseSelector(expression)
+	}
+
+	if err == nil {
+		var templateText string
+		templateText, err = fs.ReadTextFile(entry.Template)
+		if err == nil {
+			output, err = RenderTemplate(entry.Template, templateText, partials, release, selector)
+		}
+	}
+
+	return output, err
+}
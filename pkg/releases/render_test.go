@@ -0,0 +1,118 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"bytes"
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createManifestFs() *utils.MockFileSystem {
+	fs := utils.NewOverridableMockFileSystem()
+	fs.WriteTextFile("/templates/list.template", `{{ range .Artifacts }}{{ .ArtifactId }} {{ end }}`)
+	fs.WriteTextFile("/manifest.yaml", `- template: /templates/list.template
+  output: /out/obr.txt
+  preset: obr
+- template: /templates/list.template
+  output: /out/api.txt
+  selector: section == api
+`)
+	return fs
+}
+
+func TestCanReadTemplateManifest(t *testing.T) {
+	fs := createManifestFs()
+
+	entries, err := ReadTemplateManifest(fs, "/manifest.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, []TemplateManifestEntry{
+		{Template: "/templates/list.template", Output: "/out/obr.txt", Preset: "obr"},
+		{Template: "/templates/list.template", Output: "/out/api.txt", Selector: "section == api"},
+	}, entries)
+}
+
+func TestTemplateManifestRejectsBadEntries(t *testing.T) {
+	fs := utils.NewOverridableMockFileSystem()
+
+	fs.WriteTextFile("/manifest.yaml", "- template: a\n  output: b\n  selecter: obr\n")
+	_, err := ReadTemplateManifest(fs, "/manifest.yaml")
+	assert.NotNil(t, err)
+
+	fs.WriteTextFile("/manifest.yaml", "- template: a\n  output: b\n")
+	_, err = ReadTemplateManifest(fs, "/manifest.yaml")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "must have either a selector or a preset")
+
+	fs.WriteTextFile("/manifest.yaml", "- template: a\n  selector: obr\n")
+	_, err = ReadTemplateManifest(fs, "/manifest.yaml")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "entry 1 has no output")
+}
+
+func TestManifestPathsAreRelativeToTheManifestFolder(t *testing.T) {
+	fs := createManifestFs()
+	fs.WriteTextFile("/build/templates.yaml", `- template: obr/pom.template
+  output: obr/pom.xml
+  preset: obr
+- template: /templates/list.template
+  output: "-"
+  preset: bom
+`)
+
+	entries, err := ReadTemplateManifest(fs, "/build/templates.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "/build/obr/pom.template", entries[0].Template)
+	assert.Equal(t, "/build/obr/pom.xml", entries[0].Output)
+	assert.Equal(t, "/templates/list.template", entries[1].Template)
+	assert.Equal(t, STDOUT_OUTPUT_FILE_PATH, entries[1].Output)
+}
+
+func TestCanRenderAllManifestOutputs(t *testing.T) {
+	fs := createManifestFs()
+	entries, _ := ReadTemplateManifest(fs, "/manifest.yaml")
+
+	outputs, err := renderManifestOutputs(fs, entries, nil, createSelectorTestRelease())
+	assert.Nil(t, err)
+
+	var stdout, progress bytes.Buffer
+	err = writeRenderedOutputs(fs, outputs, &stdout, &progress)
+	assert.Nil(t, err)
+	assert.Contains(t, progress.String(), "Rendered /out/obr.txt from /templates/list.template")
+
+	obr, _ := fs.ReadTextFile("/out/obr.txt")
+	assert.Equal(t, "dev.galasa.framework dev.galasa.zos.manager ", obr)
+	api, _ := fs.ReadTextFile("/out/api.txt")
+	assert.Equal(t, "dev.galasa.api ", api)
+}
+
+func TestNoManifestOutputsAreWrittenIfATemplateFails(t *testing.T) {
+	fs := createManifestFs()
+	fs.WriteTextFile("/templates/broken.template", `{{ range .Artifacts | filterByFlag "shiny" }}{{ end }}`)
+	entries, _ := ReadTemplateManifest(fs, "/manifest.yaml")
+	entries = append(entries, TemplateManifestEntry{Template: "/templates/broken.template", Output: "/out/broken.txt", Selector: "true"})
+
+	_, err := renderManifestOutputs(fs, entries, nil, createSelectorTestRelease())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "no output files have been written")
+
+	for _, entry := range entries {
+		isWritten, _ := fs.Exists(entry.Output)
+		assert.False(t, isWritten, entry.Output)
+	}
+}
+
+func TestManifestWithUnknownPresetFails(t *testing.T) {
+	fs := createManifestFs()
+	entries := []TemplateManifestEntry{{Template: "/templates/list.template", Output: "/out/x.txt", Preset: "shiny"}}
+
+	_, err := renderManifestOutputs(fs, entries, nil, createSelectorTestRelease())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown selector preset 'shiny'")
+}
@@ -0,0 +1,462 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+)
+
+// A bundle from a release, along with the name of the section of the release it came from.
+type SelectableBundle struct {
+	Section string
+	Bundle  galasayaml.Bundle
+}
+
+// A Selector decides whether a bundle is wanted, based on an expression such as
+// `section in [framework,api] && bom && !isolated`
+//
+// Expressions can use:
+//   - the bundle flags obr, bom, isolated, mvp, javadoc, managerdoc and codecoverage, which are true or false.
+//   - the bundle fields section, group, artifact, version and type, compared with == or != to a value,
+//     or with `in [value1,value2]` to a list of values. Values may be quoted with ' or ".
+//   - true and false.
+//   - ! for not, && for and, || for or, and brackets.
+type Selector interface {
+	IsSelected(bundle SelectableBundle) bool
+}
+
+// The selector expressions which match the behaviour of the original artifact type flags of the
+// template command, keyed by flag name.
+var SelectorPresets = map[string]string{
+	"obr":          "obr",
+	"bom":          "bom",
+	"mvp":          "mvp",
+	"isolated":     "section != external || isolated",
+	"javadoc":      "(section in [framework,api] && javadoc) || section == managers",
+	"managerdoc":   "(section in [framework,api] && managerdoc) || section == managers",
+	"codecoverage": "codecoverage",
+}
+
+// GetSelectorPresetNames returns the names of the selector presets in alphabetical order.
+func GetSelectorPresetNames() []string {
+	names := make([]string, 0, len(SelectorPresets))
+	for name := range SelectorPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseSelector turns a selector expression into a Selector.
+func ParseSelector(expression string) (Selector, error) {
+	var selector Selector
+
+	tokens, err := tokenizeSelector(expression)
+	if err == nil {
+		parser := selectorParser{tokens: tokens}
+		selector, err = parser.parseOr()
+		if err == nil && !parser.isAtEnd() {
+			err = fmt.Errorf("unexpected '%s'", parser.peek().text)
+		}
+	}
+
+	if err != nil {
+		err = fmt.Errorf("invalid selector expression '%s' - %s", expression, err.Error())
+	}
+
+	return selector, err
+}
+
+// SelectBundles returns the bundles of every section of the release which the selector picks,
+// in the order they appear in the release. Bundles with no group are given the default
+// group of dev.galasa before the selector sees them.
+func SelectBundles(release *galasayaml.Release, selector Selector) []SelectableBundle {
+	var selected []SelectableBundle
+	for _, sectionName := range galasayaml.SectionNames {
+		for _, bundle := range *release.GetSectionBundles(sectionName) {
+			bundle.Group = bundle.GetGroup()
+
+			selectable := SelectableBundle{Section: sectionName, Bundle: bundle}
+			if selector.IsSelected(selectable) {
+				selected = append(selected, selectable)
+			}
+		}
+	}
+	return selected
+}
+
+func getBundleFlag(bundle SelectableBundle, flagName string) bool {
+	var value bool
+	switch flagName {
+	case "obr":
+		value = bundle.Bundle.Obr
+	case "bom":
+		value = bundle.Bundle.Bom
+	case "isolated":
+		value = bundle.Bundle.Isolated
+	case "mvp":
+		value = bundle.Bundle.Mvp
+	case "javadoc":
+		value = bundle.Bundle.Javadoc
+	case "managerdoc":
+		value = bundle.Bundle.Managerdoc
+	case "codecoverage":
+		value = bundle.Bundle.Codecoverage
+	}
+	return value
+}
+
+func getBundleField(bundle SelectableBundle, fieldName string) string {
+	var value string
+	switch fieldName {
+	case "section":
+		value = bundle.Section
+	case "group":
+		value = bundle.Bundle.Group
+	case "artifact":
+		value = bundle.Bundle.Artifact
+	case "version":
+		value = bundle.Bundle.Version
+	case "type":
+		value = bundle.Bundle.Type
+	}
+	return value
+}
+
+func isBundleFlag(name string) bool {
+	switch name {
+	case "obr", "bom", "isolated", "mvp", "javadoc", "managerdoc", "codecoverage":
+		return true
+	}
+	return false
+}
+
+func isBundleField(name string) bool {
+	switch name {
+	case "section", "group", "artifact", "version", "type":
+		return true
+	}
+	return false
+}
+
+// ------------------------------------------------------------------------------------
+// The nodes of a parsed selector expression.
+// ------------------------------------------------------------------------------------
+
+type constantSelector struct {
+	value bool
+}
+
+func (selector constantSelector) IsSelected(bundle SelectableBundle) bool {
+	return selector.value
+}
+
+type flagSelector struct {
+	flagName string
+}
+
+func (selector flagSelector) IsSelected(bundle SelectableBundle) bool {
+	return getBundleFlag(bundle, selector.flagName)
+}
+
+type fieldInSelector struct {
+	fieldName string
+	values    []string
+}
+
+func (selector fieldInSelector) IsSelected(bundle SelectableBundle) bool {
+	isFound := false
+	fieldValue := getBundleField(bundle, selector.fieldName)
+	for _, value := range selector.values {
+		if fieldValue == value {
+			isFound = true
+			break
+		}
+	}
+	return isFound
+}
+
+type notSelector struct {
+	operand Selector
+}
+
+func (selector notSelector) IsSelected(bundle SelectableBundle) bool {
+	return !selector.operand.IsSelected(bundle)
+}
+
+type andSelector struct {
+	left  Selector
+	right Selector
+}
+
+func (selector andSelector) IsSelected(bundle SelectableBundle) bool {
+	return selector.left.IsSelected(bundle) && selector.right.IsSelected(bundle)
+}
+
+type orSelector struct {
+	left  Selector
+	right Selector
+}
+
+func (selector orSelector) IsSelected(bundle SelectableBundle) bool {
+	return selector.left.IsSelected(bundle) || selector.right.IsSelected(bundle)
+}
+
+// ------------------------------------------------------------------------------------
+// Turning the expression text into tokens.
+// ------------------------------------------------------------------------------------
+
+const (
+	TOKEN_WORD   = "word"
+	TOKEN_STRING = "string"
+	TOKEN_SYMBOL = "symbol"
+)
+
+type selectorToken struct {
+	kind string
+	text string
+}
+
+func tokenizeSelector(expression string) ([]selectorToken, error) {
+	var err error
+	var tokens []selectorToken
+
+	runes := []rune(expression)
+	for index := 0; index < len(runes) && err == nil; {
+		character := runes[index]
+
+		switch {
+		case unicode.IsSpace(character):
+			index++
+
+		case strings.ContainsRune("()[],", character):
+			tokens = append(tokens, selectorToken{kind: TOKEN_SYMBOL, text: string(character)})
+			index++
+
+		case character == '\'' || character == '"':
+			end := index + 1
+			for end < len(runes) && runes[end] != character {
+				end++
+			}
+			if end >= len(runes) {
+				err = fmt.Errorf("missing closing %c", character)
+			} else {
+				tokens = append(tokens, selectorToken{kind: TOKEN_STRING, text: string(runes[index+1 : end])})
+				index = end + 1
+			}
+
+		case strings.ContainsRune("!=&|", character):
+			// Two character operators, or ! on its own.
+			twoCharacters := ""
+			if index+1 < len(runes) {
+				twoCharacters = string(runes[index : index+2])
+			}
+			switch twoCharacters {
+			case "&&", "||", "==", "!=":
+				tokens = append(tokens, selectorToken{kind: TOKEN_SYMBOL, text: twoCharacters})
+				index += 2
+			default:
+				if character == '!' {
+					tokens = append(tokens, selectorToken{kind: TOKEN_SYMBOL, text: "!"})
+					index++
+				} else {
+					err = fmt.Errorf("unexpected '%c'", character)
+				}
+			}
+
+		case isWordCharacter(character):
+			end := index
+			for end < len(runes) && isWordCharacter(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, selectorToken{kind: TOKEN_WORD, text: string(runes[index:end])})
+			index = end
+
+		default:
+			err = fmt.Errorf("unexpected '%c'", character)
+		}
+	}
+
+	return tokens, err
+}
+
+func isWordCharacter(character rune) bool {
+	return unicode.IsLetter(character) || unicode.IsDigit(character) || strings.ContainsRune("._-", character)
+}
+
+// ------------------------------------------------------------------------------------
+// Parsing the tokens into selector nodes.
+// ------------------------------------------------------------------------------------
+
+type selectorParser struct {
+	tokens []selectorToken
+	next   int
+}
+
+func (parser *selectorParser) isAtEnd() bool {
+	return parser.next >= len(parser.tokens)
+}
+
+func (parser *selectorParser) peek() selectorToken {
+	var token selectorToken
+	if !parser.isAtEnd() {
+		token = parser.tokens[parser.next]
+	}
+	return token
+}
+
+func (parser *selectorParser) isNextSymbol(symbol string) bool {
+	token := parser.peek()
+	return !parser.isAtEnd() && token.kind == TOKEN_SYMBOL && token.text == symbol
+}
+
+func (parser *selectorParser) expectSymbol(symbol string) error {
+	var err error
+	if parser.isNextSymbol(symbol) {
+		parser.next++
+	} else if parser.isAtEnd() {
+		err = fmt.Errorf("expected '%s' but the expression ended", symbol)
+	} else {
+		err = fmt.Errorf("expected '%s' but found '%s'", symbol, parser.peek().text)
+	}
+	return err
+}
+
+func (parser *selectorParser) parseOr() (Selector, error) {
+	selector, err := parser.parseAnd()
+	for err == nil && parser.isNextSymbol("||") {
+		parser.next++
+		var right Selector
+		right, err = parser.parseAnd()
+		selector = orSelector{left: selector, right: right}
+	}
+	return selector, err
+}
+
+func (parser *selectorParser) parseAnd() (Selector, error) {
+	selector, err := parser.parseUnary()
+	for err == nil && parser.isNextSymbol("&&") {
+		parser.next++
+		var right Selector
+		right, err = parser.parseUnary()
+		selector = andSelector{left: selector, right: right}
+	}
+	return selector, err
+}
+
+func (parser *selectorParser) parseUnary() (Selector, error) {
+	var selector Selector
+	var err error
+
+	if parser.isNextSymbol("!") {
+		parser.next++
+		var operand Selector
+		operand, err = parser.parseUnary()
+		selector = notSelector{operand: operand}
+	} else {
+		selector, err = parser.parsePrimary()
+	}
+
+	return selector, err
+}
+
+func (parser *selectorParser) parsePrimary() (Selector, error) {
+	var selector Selector
+	var err error
+
+	token := parser.peek()
+
+	if parser.isAtEnd() {
+		err = fmt.Errorf("the expression ended unexpectedly")
+	} else if parser.isNextSymbol("(") {
+		parser.next++
+		selector, err = parser.parseOr()
+		if err == nil {
+			err = parser.expectSymbol(")")
+		}
+	} else if token.kind != TOKEN_WORD {
+		err = fmt.Errorf("unexpected '%s'", token.text)
+	} else {
+		parser.next++
+		switch {
+		case token.text == "true" || token.text == "false":
+			selector = constantSelector{value: token.text == "true"}
+		case isBundleFlag(token.text):
+			selector = flagSelector{flagName: token.text}
+		case isBundleField(token.text):
+			selector, err = parser.parseFieldComparison(token.text)
+		default:
+			err = fmt.Errorf("unknown name '%s'", token.text)
+		}
+	}
+
+	return selector, err
+}
+
+// parseFieldComparison parses the rest of `field == value`, `field != value` or `field in [value,...]`
+func (parser *selectorParser) parseFieldComparison(fieldName string) (Selector, error) {
+	var selector Selector
+	var err error
+
+	switch {
+	case parser.isNextSymbol("==") || parser.isNextSymbol("!="):
+		operator := parser.peek().text
+		parser.next++
+
+		var value string
+		value, err = parser.parseValue()
+		selector = fieldInSelector{fieldName: fieldName, values: []string{value}}
+		if operator == "!=" {
+			selector = notSelector{operand: selector}
+		}
+
+	case parser.peek().kind == TOKEN_WORD && parser.peek().text == "in":
+		parser.next++
+		var values []string
+		err = parser.expectSymbol("[")
+		for err == nil {
+			var value string
+			value, err = parser.parseValue()
+			values = append(values, value)
+			if err == nil && !parser.isNextSymbol(",") {
+				break
+			}
+			parser.next++
+		}
+		if err == nil {
+			err = parser.expectSymbol("]")
+		}
+		selector = fieldInSelector{fieldName: fieldName, values: values}
+
+	default:
+		err = fmt.Errorf("expected '==', '!=' or 'in' after '%s'", fieldName)
+	}
+
+	return selector, err
+}
+
+func (parser *selectorParser) parseValue() (string, error) {
+	var value string
+	var err error
+
+	token := parser.peek()
+	if parser.isAtEnd() {
+		err = fmt.Errorf("expected a value but the expression ended")
+	} else if token.kind == TOKEN_SYMBOL {
+		err = fmt.Errorf("expected a value but found '%s'", token.text)
+	} else {
+		value = token.text
+		parser.next++
+	}
+
+	return value, err
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func createSelectorTestRelease() galasayaml.Release {
+	release := NewRelease()
+	release.Framework.Bundles = []galasayaml.Bundle{
+		{Artifact: "dev.galasa.framework", Version: "0.36.0", Obr: true, Bom: true, Javadoc: true},
+		{Artifact: "galasa-boot", Version: "0.36.0", Isolated: true},
+	}
+	release.Api.Bundles = []galasayaml.Bundle{
+		{Artifact: "dev.galasa.api", Version: "0.36.0", Bom: true, Managerdoc: true},
+	}
+	release.Managers.Bundles = []galasayaml.Bundle{
+		{Artifact: "dev.galasa.zos.manager", Version: "0.36.0", Obr: true, Bom: true, Codecoverage: true},
+	}
+	release.External.Bundles = []galasayaml.Bundle{
+		{Group: "org.example", Artifact: "ext.isolated", Version: "1.0.0", Isolated: true, Bom: true},
+		{Group: "org.example", Artifact: "ext.other", Version: "2.0.0", Type: "jar"},
+	}
+	return release
+}
+
+func selectArtifactNames(t *testing.T, expression string) []string {
+	release := createSelectorTestRelease()
+	selector, err := ParseSelector(expression)
+	assert.Nil(t, err)
+
+	names := []string{}
+	for _, selected := range SelectBundles(&release, selector) {
+		names = append(names, selected.Bundle.Artifact)
+	}
+	return names
+}
+
+func TestCanSelectByFlag(t *testing.T) {
+	assert.Equal(t, []string{"dev.galasa.framework", "dev.galasa.zos.manager"}, selectArtifactNames(t, "obr"))
+}
+
+func TestCanSelectBySectionAndFlags(t *testing.T) {
+	assert.Equal(t, []string{"dev.galasa.framework", "dev.galasa.api"},
+		selectArtifactNames(t, "section in [framework,api] && bom && !isolated"))
+}
+
+func TestCanSelectByFieldComparisons(t *testing.T) {
+	assert.Equal(t, []string{"ext.other"}, selectArtifactNames(t, "group == 'org.example' && type != \"\""))
+	assert.Equal(t, []string{"dev.galasa.framework", "galasa-boot", "dev.galasa.api", "dev.galasa.zos.manager"},
+		selectArtifactNames(t, "group == dev.galasa"))
+	assert.Equal(t, []string{"ext.isolated"}, selectArtifactNames(t, "version == 1.0.0"))
+}
+
+func TestAndBindsMoreTightlyThanOr(t *testing.T) {
+	assert.Equal(t, []string{"dev.galasa.framework", "galasa-boot", "ext.isolated"},
+		selectArtifactNames(t, "isolated || section == framework && obr"))
+	assert.Equal(t, []string{"dev.galasa.framework", "galasa-boot"},
+		selectArtifactNames(t, "(isolated || obr) && section == framework"))
+}
+
+func TestCanSelectEverythingOrNothing(t *testing.T) {
+	assert.Len(t, selectArtifactNames(t, "true"), 6)
+	assert.Len(t, selectArtifactNames(t, "!true || false"), 0)
+}
+
+func TestPresetsMatchOriginalArtifactTypeFlags(t *testing.T) {
+	assert.Equal(t, []string{"dev.galasa.framework", "galasa-boot", "dev.galasa.api", "dev.galasa.zos.manager", "ext.isolated"},
+		selectArtifactNames(t, SelectorPresets["isolated"]))
+	assert.Equal(t, []string{"dev.galasa.framework", "dev.galasa.zos.manager"},
+		selectArtifactNames(t, SelectorPresets["javadoc"]))
+	assert.Equal(t, []string{"dev.galasa.api", "dev.galasa.zos.manager"},
+		selectArtifactNames(t, SelectorPresets["managerdoc"]))
+	assert.Equal(t, []string{"dev.galasa.zos.manager"},
+		selectArtifactNames(t, SelectorPresets["codecoverage"]))
+}
+
+func TestEveryPresetParses(t *testing.T) {
+	for _, name := range GetSelectorPresetNames() {
+		_, err := ParseSelector(SelectorPresets[name])
+		assert.Nil(t, err, name)
+	}
+}
+
+func TestInvalidSelectorsAreRejected(t *testing.T) {
+	invalidExpressions := []string{
+		"",
+		"unknownflag",
+		"obr &&",
+		"(obr",
+		"obr)",
+		"section",
+		"section in [framework",
+		"section in []",
+		"section == 'framework",
+		"obr & bom",
+		"obr ? bom",
+	}
+	for _, expression := range invalidExpressions {
+		_, err := ParseSelector(expression)
+		assert.NotNil(t, err, expression)
+	}
+}
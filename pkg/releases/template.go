@@ -0,0 +1,221 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+// The output file path which means the rendered template is written to stdout.
+const STDOUT_OUTPUT_FILE_PATH = "-"
+
+// What the template command has been asked to do.
+type TemplateOptions struct {
+	ReleaseMetadataFilePaths []string
+	// How to merge bundles with the same group:artifact from different release metadata files. One of MergeStrategies.
+	MergeStrategy string
+
+	TemplateFilePath string
+	OutputFilePath   string
+	// A selector expression picking the artifacts to use.
+	Selector string
+	// The names of the SelectorPresets which were asked for. Only one may be used, instead of a Selector.
+	Presets []string
+
+	// A manifest listing several templates to render, used instead of the template, output and selection options.
+	ManifestFilePath string
+	// A folder of templates which can be included by other templates.
+	TemplateFolderPath string
+
+	// Compare the rendered templates with the existing output files, instead of writing them.
+	IsCheckMode bool
+}
+
+// TemplateExecute renders a template, or each template in a manifest, using the merged release metadata.
+//
+// Rendered templates with an output of '-' are written to stdout, and progress messages to the progress writer.
+func TemplateExecute(fs utils.FileSystem, options TemplateOptions, stdout io.Writer, progress io.Writer) error {
+	var err error
+	var release galasayaml.Release
+	var partials map[string]string
+	var outputs []RenderedOutput
+
+	err = validateTemplateOptions(options)
+
+	if err == nil {
+		var conflicts []galasayaml.MergeConflict
+		release, conflicts, err = ReadAndMergeReleaseFiles(fs, options.ReleaseMetadataFilePaths, options.MergeStrategy)
+		printMergeConflicts(progress, conflicts)
+	}
+
+	if err == nil && release.Release.Version == "" {
+		err = errors.New("the release version is not provided in the release metadata files")
+	}
+
+	if err == nil {
+		partials = make(map[string]string)
+		if options.TemplateFolderPath != "" {
+			partials, err = LoadTemplatePartials(fs, options.TemplateFolderPath)
+		}
+	}
+
+	if err == nil {
+		if options.ManifestFilePath != "" {
+			var entries []TemplateManifestEntry
+			entries, err = ReadTemplateManifest(fs, options.ManifestFilePath)
+			if err == nil {
+				outputs, err = renderManifestOutputs(fs, entries, partials, release)
+			}
+		} else {
+			var output RenderedOutput
+			output, err = renderTemplateFile(fs, options, partials, release, progress)
+			outputs = []RenderedOutput{output}
+		}
+	}
+
+	if err == nil {
+		if options.IsCheckMode {
+			err = checkRenderedOutputs(fs, outputs, stdout, progress)
+		} else {
+			err = writeRenderedOutputs(fs, outputs, stdout, progress)
+		}
+	}
+
+	return err
+}
+
+func validateTemplateOptions(options TemplateOptions) error {
+	var err error
+
+	if len(options.ReleaseMetadataFilePaths) == 0 {
+		err = errors.New("no release metadata files have been provided, use --releaseMetadata")
+	} else if options.ManifestFilePath != "" {
+		if options.TemplateFilePath != "" || options.OutputFilePath != "" || options.Selector != "" || len(options.Presets) > 0 {
+			err = errors.New("--manifest cannot be used with --template, --output, --select or the artifact type flags")
+		}
+	} else if options.TemplateFilePath == "" {
+		err = errors.New("no template file has been provided, use --template or --manifest")
+	} else if options.OutputFilePath == "" {
+		err = errors.New("no output file has been provided, use --output, or --output - for stdout")
+	} else if options.IsCheckMode && options.OutputFilePath == STDOUT_OUTPUT_FILE_PATH {
+		err = errors.New("--check needs an output file to compare with, so cannot be used with --output -")
+	} else {
+		_, err = getTemplateSelectorExpression(options)
+	}
+
+	return err
+}
+
+// getTemplateSelectorExpression works out which selector expression to use, either the one given
+// with --select or the preset for the one artifact type flag which was set.
+func getTemplateSelectorExpression(options TemplateOptions) (string, error) {
+	var err error
+	var expression string
+
+	if options.Selector != "" {
+		if len(options.Presets) > 0 {
+			err = errors.New("--select cannot be used with an artifact type flag")
+		}
+		expression = options.Selector
+	} else if len(options.Presets) == 0 {
+		err = errors.New("no artifact type has been provided, use --select or one of the artifact type flags")
+	} else if len(options.Presets) > 1 {
+		err = fmt.Errorf("too many artifact types have been requested: %s", strings.Join(options.Presets, ", "))
+	} else {
+		var isFound bool
+		expression, isFound = SelectorPresets[options.Presets[0]]
+		if !isFound {
+			err = fmt.Errorf("unknown selector preset '%s'", options.Presets[0])
+		}
+	}
+
+	return expression, err
+}
+
+// ReadAndMergeReleaseFiles reads each release metadata file, and merges them using the merge strategy.
+func ReadAndMergeReleaseFiles(fs utils.FileSystem, releaseFilePaths []string, mergeStrategy string) (galasayaml.Release, []galasayaml.MergeConflict, error) {
+	var err error
+	var merged galasayaml.Release
+	var conflicts []galasayaml.MergeConflict
+	var inputReleases []galasayaml.Release
+
+	for _, releaseFilePath := range releaseFilePaths {
+		var inputRelease galasayaml.Release
+		inputRelease, err = ReadReleaseFile(fs, releaseFilePath)
+		if err != nil {
+			break
+		}
+		inputReleases = append(inputReleases, inputRelease)
+	}
+
+	if err == nil {
+		merged, conflicts, err = galasayaml.MergeReleases(inputReleases, mergeStrategy)
+	}
+
+	return merged, conflicts, err
+}
+
+func printMergeConflicts(writer io.Writer, conflicts []galasayaml.MergeConflict) {
+	for _, conflict := range conflicts {
+		fmt.Fprintf(writer, "Conflict: %v\n", conflict)
+	}
+}
+
+func renderTemplateFile(
+	fs utils.FileSystem,
+	options TemplateOptions,
+	partials map[string]string,
+	release galasayaml.Release,
+	progress io.Writer,
+) (RenderedOutput, error) {
+	var selector Selector
+	var templateText string
+
+	output := RenderedOutput{TemplateFilePath: options.TemplateFilePath, OutputFilePath: options.OutputFilePath}
+
+	expression, err := getTemplateSelectorExpression(options)
+	if err == nil {
+		fmt.Fprintf(progress, "Artifacts selected by expression: %v\n", expression)
+		selector, err = ParseSelector(expression)
+	}
+
+	if err == nil {
+		printSelectedArtifacts(progress, NewTemplateData(release, SelectBundles(&release, selector)))
+
+		templateText, err = fs.ReadTextFile(options.TemplateFilePath)
+		if err != nil {
+			err = fmt.Errorf("failed to read template file %s - %s", options.TemplateFilePath, err.Error())
+		}
+	}
+
+	if err == nil {
+		output.Contents, err = RenderTemplate(options.TemplateFilePath, templateText, partials, release, selector)
+		if err != nil {
+			err = fmt.Errorf("failed to render template %s - %s", options.TemplateFilePath, err.Error())
+		}
+	}
+
+	return output, err
+}
+
+func printSelectedArtifacts(writer io.Writer, data TemplateData) {
+	fmt.Fprintf(writer, "Release version is %v\n", data.Release)
+
+	for _, artifact := range data.Artifacts {
+		fmt.Fprintf(writer, "    Added %v artifact %v:%v:%v\n", artifact.Section, artifact.GroupId, artifact.ArtifactId, artifact.Version)
+	}
+
+	if data.BootRelease != "" {
+		fmt.Fprintf(writer, "    Set galasa-boot version to %v\n", data.BootRelease)
+	}
+}
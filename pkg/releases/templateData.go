@@ -0,0 +1,189 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"fmt"
+	"sort"
+	"text/template"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+// The data which a template is given when it is executed.
+type TemplateData struct {
+	// The version of the release.
+	Release string
+	// The selected artifacts, in the order they appear in the release.
+	Artifacts []TemplateArtifact
+	// The version of the galasa-boot bundle in the framework section, if there is one.
+	BootRelease string
+
+	Name       string
+	ApiVersion string
+	// The whole release, after all the release metadata files have been merged.
+	ReleaseMetadata galasayaml.Release
+}
+
+// An artifact of the release which was picked by the selector.
+type TemplateArtifact struct {
+	GroupId    string
+	ArtifactId string
+	Version    string
+	Type       string
+
+	// The section of the release the artifact came from, eg. framework
+	Section string
+
+	Obr          bool
+	Bom          bool
+	Isolated     bool
+	Mvp          bool
+	Javadoc      bool
+	Managerdoc   bool
+	Codecoverage bool
+}
+
+// A set of artifacts which share the same group.
+type TemplateArtifactGroup struct {
+	GroupId   string
+	Artifacts []TemplateArtifact
+}
+
+// NewTemplateData builds the data for a template from the merged release and the bundles which were selected from it.
+func NewTemplateData(release galasayaml.Release, selectedBundles []SelectableBundle) TemplateData {
+	data := TemplateData{
+		Release:         release.Release.Version,
+		Name:            release.Metadata.Name,
+		ApiVersion:      release.ApiVersion,
+		ReleaseMetadata: release,
+	}
+
+	for _, selected := range selectedBundles {
+		data.Artifacts = append(data.Artifacts, NewTemplateArtifact(selected))
+	}
+
+	for _, bundle := range release.Framework.Bundles {
+		if bundle.Artifact == "galasa-boot" {
+			data.BootRelease = bundle.Version
+		}
+	}
+
+	return data
+}
+
+// NewTemplateArtifact turns a selected bundle into the artifact a template sees.
+func NewTemplateArtifact(selected SelectableBundle) TemplateArtifact {
+	bundle := selected.Bundle
+	return TemplateArtifact{
+		GroupId:      bundle.Group,
+		ArtifactId:   bundle.Artifact,
+		Version:      bundle.Version,
+		Type:         bundle.Type,
+		Section:      selected.Section,
+		Obr:          bundle.Obr,
+		Bom:          bundle.Bom,
+		Isolated:     bundle.Isolated,
+		Mvp:          bundle.Mvp,
+		Javadoc:      bundle.Javadoc,
+		Managerdoc:   bundle.Managerdoc,
+		Codecoverage: bundle.Codecoverage,
+	}
+}
+
+func (artifact TemplateArtifact) toSelectableBundle() SelectableBundle {
+	return SelectableBundle{
+		Section: artifact.Section,
+		Bundle: galasayaml.Bundle{
+			Group:        artifact.GroupId,
+			Artifact:     artifact.ArtifactId,
+			Version:      artifact.Version,
+			Type:         artifact.Type,
+			Obr:          artifact.Obr,
+			Bom:          artifact.Bom,
+			Isolated:     artifact.Isolated,
+			Mvp:          artifact.Mvp,
+			Javadoc:      artifact.Javadoc,
+			Managerdoc:   artifact.Managerdoc,
+			Codecoverage: artifact.Codecoverage,
+		},
+	}
+}
+
+// GetTemplateFunctions returns the helper functions which templates can use.
+//
+//   - sortByGAV sorts artifacts by group, then artifact, then version. eg. {{ range sortByGAV .Artifacts }}
+//   - groupByGroupId splits artifacts into groups, sorted by group. eg. {{ range groupByGroupId .Artifacts }}{{ .GroupId }}...
+//   - filterByFlag keeps the artifacts which have a flag set. eg. {{ range .Artifacts | filterByFlag "bom" }}
+//
+// The general purpose functions in generalTemplateFunctions can be used too.
+func GetTemplateFunctions() template.FuncMap {
+	functions := template.FuncMap{
+		"sortByGAV":      sortArtifactsByGAV,
+		"groupByGroupId": groupArtifactsByGroupId,
+		"filterByFlag":   filterArtifactsByFlag,
+	}
+	for name, function := range generalTemplateFunctions {
+		functions[name] = function
+	}
+	return functions
+}
+
+func sortArtifactsByGAV(artifacts []TemplateArtifact) []TemplateArtifact {
+	sorted := make([]TemplateArtifact, len(artifacts))
+	copy(sorted, artifacts)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.GroupId != b.GroupId {
+			return a.GroupId < b.GroupId
+		}
+		if a.ArtifactId != b.ArtifactId {
+			return a.ArtifactId < b.ArtifactId
+		}
+		return utils.CompareVersions(a.Version, b.Version) < 0
+	})
+	return sorted
+}
+
+func groupArtifactsByGroupId(artifacts []TemplateArtifact) []TemplateArtifactGroup {
+	groupIndexes := make(map[string]int)
+	var groups []TemplateArtifactGroup
+
+	for _, artifact := range artifacts {
+		index, isKnown := groupIndexes[artifact.GroupId]
+		if !isKnown {
+			index = len(groups)
+			groupIndexes[artifact.GroupId] = index
+			groups = append(groups, TemplateArtifactGroup{GroupId: artifact.GroupId})
+		}
+		groups[index].Artifacts = append(groups[index].Artifacts, artifact)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].GroupId < groups[j].GroupId
+	})
+	return groups
+}
+
+func filterArtifactsByFlag(flagName string, artifacts []TemplateArtifact) ([]TemplateArtifact, error) {
+	var err error
+	var filtered []TemplateArtifact
+
+	if !isBundleFlag(flagName) {
+		err = fmt.Errorf("unknown bundle flag '%s'", flagName)
+	} else {
+		for _, artifact := range artifacts {
+			if getBundleFlag(artifact.toSelectableBundle(), flagName) {
+				filtered = append(filtered, artifact)
+			}
+		}
+	}
+
+	return filtered, err
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func executeTestTemplate(t *testing.T, templateText string, expression string) (string, error) {
+	release := createSelectorTestRelease()
+	release.Release.Version = "0.36.0"
+	release.Metadata.Name = "my-release"
+
+	selector, err := ParseSelector(expression)
+	assert.Nil(t, err)
+	data := NewTemplateData(release, SelectBundles(&release, selector))
+
+	tmpl, err := template.New("test").Funcs(GetTemplateFunctions()).Parse(templateText)
+	assert.Nil(t, err)
+
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, data)
+	return buffer.String(), err
+}
+
+func TestTemplateDataHasReleaseMetadata(t *testing.T) {
+	release := createSelectorTestRelease()
+	release.Metadata.Name = "my-release"
+
+	data := NewTemplateData(release, nil)
+
+	assert.Equal(t, "my-release", data.Name)
+	assert.Equal(t, DEFAULT_API_VERSION, data.ApiVersion)
+	assert.Equal(t, "0.36.0", data.BootRelease)
+	assert.Len(t, data.ReleaseMetadata.External.Bundles, 2)
+}
+
+func TestTemplateArtifactsHaveSectionAndFlags(t *testing.T) {
+	output, err := executeTestTemplate(t,
+		`{{ range .Artifacts }}{{ .Section }} {{ .ArtifactId }} {{ .Bom }} {{ .Isolated }}
+{{ end }}`, "section in [api,external] && bom")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "api dev.galasa.api true false\nexternal ext.isolated true true\n", output)
+}
+
+func TestTemplateCanUseWholeRelease(t *testing.T) {
+	output, err := executeTestTemplate(t,
+		`{{ .Name }} {{ .ApiVersion }}{{ range .ReleaseMetadata.Managers.Bundles }} {{ .Artifact }}{{ end }}`, "false")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "my-release galasa.dev/v1alpha dev.galasa.zos.manager", output)
+}
+
+func TestTemplateCanSortArtifactsByGAV(t *testing.T) {
+	output, err := executeTestTemplate(t,
+		`{{ range sortByGAV .Artifacts }}{{ .GroupId }}:{{ .ArtifactId }} {{ end }}`, "bom")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "dev.galasa:dev.galasa.api dev.galasa:dev.galasa.framework dev.galasa:dev.galasa.zos.manager org.example:ext.isolated ", output)
+}
+
+func TestSortByGAVOrdersVersionsNumerically(t *testing.T) {
+	artifacts := []TemplateArtifact{
+		{GroupId: "dev.galasa", ArtifactId: "dev.galasa.framework", Version: "0.10.0"},
+		{GroupId: "dev.galasa", ArtifactId: "dev.galasa.framework", Version: "0.9.0"},
+	}
+
+	sorted := sortArtifactsByGAV(artifacts)
+
+	assert.Equal(t, "0.9.0", sorted[0].Version)
+	assert.Equal(t, "0.10.0", sorted[1].Version)
+}
+
+func TestTemplateCanGroupArtifactsByGroupId(t *testing.T) {
+	output, err := executeTestTemplate(t,
+		`{{ range groupByGroupId .Artifacts }}{{ .GroupId }}={{ len .Artifacts }} {{ end }}`, "true")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "dev.galasa=4 org.example=2 ", output)
+}
+
+func TestTemplateCanFilterArtifactsByFlag(t *testing.T) {
+	output, err := executeTestTemplate(t,
+		`{{ range .Artifacts | filterByFlag "obr" }}{{ .ArtifactId }} {{ end }}`, "true")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "dev.galasa.framework dev.galasa.zos.manager ", output)
+}
+
+func TestTemplateFilterByUnknownFlagFails(t *testing.T) {
+	_, err := executeTestTemplate(t, `{{ range .Artifacts | filterByFlag "shiny" }}{{ end }}`, "true")
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown bundle flag 'shiny'")
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+// The general purpose functions which templates can use, as well as the artifact functions.
+//
+// Like the functions of the text/template package, the value being worked on is the last parameter,
+// so they can be used at the end of a pipeline. eg. {{ .Name | default "galasa" | upper }}
+var generalTemplateFunctions = map[string]interface{}{
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix string, value string) string { return strings.TrimPrefix(value, prefix) },
+	"trimSuffix": func(suffix string, value string) string { return strings.TrimSuffix(value, suffix) },
+	"replace":    func(old string, new string, value string) string { return strings.ReplaceAll(value, old, new) },
+	"contains":   func(part string, value string) bool { return strings.Contains(value, part) },
+	"hasPrefix":  func(prefix string, value string) bool { return strings.HasPrefix(value, prefix) },
+	"hasSuffix":  func(suffix string, value string) bool { return strings.HasSuffix(value, suffix) },
+	"split":      func(separator string, value string) []string { return strings.Split(value, separator) },
+	"join":       joinTemplateValues,
+	"default":    defaultTemplateValue,
+
+	"compareVersions":  utils.CompareVersions,
+	"isVersionAtLeast": func(minimum string, version string) bool { return utils.CompareVersions(version, minimum) >= 0 },
+
+	"gavPath": gavToPath,
+}
+
+// joinTemplateValues joins a list of anything into a string, putting the separator between each.
+func joinTemplateValues(separator string, values interface{}) (string, error) {
+	var err error
+	var parts []string
+
+	list := reflect.ValueOf(values)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		err = fmt.Errorf("join needs a list, not %T", values)
+	} else {
+		for index := 0; index < list.Len(); index++ {
+			parts = append(parts, fmt.Sprint(list.Index(index).Interface()))
+		}
+	}
+
+	return strings.Join(parts, separator), err
+}
+
+// defaultTemplateValue returns the value, or the default value if the value is empty, zero, false or missing.
+func defaultTemplateValue(defaultValue interface{}, value ...interface{}) interface{} {
+	result := defaultValue
+	if len(value) > 0 && value[0] != nil && !reflect.ValueOf(value[0]).IsZero() {
+		result = value[0]
+	}
+	return result
+}
+
+// gavToPath returns the path of an artifact's folder in a maven repository.
+// eg. dev.galasa, dev.galasa.framework, 0.36.0 gives dev/galasa/dev.galasa.framework/0.36.0
+func gavToPath(groupId string, artifactId string, version string) string {
+	return path.Join(strings.ReplaceAll(groupId, ".", "/"), artifactId, version)
+}
+
+// LoadTemplatePartials reads every file in a folder, so that templates can include them with
+// {{ template "name" . }}, where the name is the file name without its extension.
+// eg. dependency.template can be included with {{ template "dependency" . }}
+func LoadTemplatePartials(fs utils.FileSystem, templateFolderPath string) (map[string]string, error) {
+	partials := make(map[string]string)
+
+	entries, err := fs.ReadDir(templateFolderPath)
+	if err == nil {
+		for _, entry := range entries {
+			fileName := entry.Name()
+			filePath := path.Join(templateFolderPath, fileName)
+
+			var isFolder bool
+			isFolder, err = fs.DirExists(filePath)
+			if err == nil && !isFolder {
+				var contents string
+				contents, err = fs.ReadTextFile(filePath)
+				if err == nil {
+					partials[strings.TrimSuffix(fileName, path.Ext(fileName))] = contents
+				}
+			}
+			if err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed to load templates from %s - %s", templateFolderPath, err.Error())
+	}
+
+	return partials, err
+}
+
+func getSortedPartialNames(partials map[string]string) []string {
+	names := make([]string, 0, len(partials))
+	for name := range partials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
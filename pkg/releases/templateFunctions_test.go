@@ -0,0 +1,73 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func renderTestTemplate(t *testing.T, templateText string, partials map[string]string) string {
+	release := createSelectorTestRelease()
+	release.Release.Version = "0.36.0"
+	selector, _ := ParseSelector("section == api || group == org.example")
+
+	output, err := RenderTemplate("test", templateText, partials, release, selector)
+	assert.Nil(t, err)
+	return string(output)
+}
+
+func TestTemplateStringFunctions(t *testing.T) {
+	output := renderTestTemplate(t,
+		`{{ "Galasa" | upper }} {{ "Galasa" | lower }} [{{ trim "  x  " }}] {{ .Release | replace "." "_" }} `+
+			`{{ trimPrefix "dev." "dev.galasa" }} {{ trimSuffix ".manager" "zos.manager" }} `+
+			`{{ contains "gal" "galasa" }} {{ hasPrefix "dev" "dev.galasa" }} {{ hasSuffix "x" "galasa" }}`, nil)
+
+	assert.Equal(t, "GALASA galasa [x] 0_36_0 galasa zos true true false", output)
+}
+
+func TestTemplateDefaultAndJoin(t *testing.T) {
+	output := renderTestTemplate(t,
+		`{{ "" | default "unnamed" }} {{ .Release | default "none" }} {{ split "." "a.b.c" | join "/" }}`, nil)
+
+	assert.Equal(t, "unnamed 0.36.0 a/b/c", output)
+}
+
+func TestTemplateVersionFunctions(t *testing.T) {
+	output := renderTestTemplate(t,
+		`{{ compareVersions "0.9.0" "0.10.0" }} {{ .Release | isVersionAtLeast "0.35.0" }} {{ isVersionAtLeast "1.0.0" .Release }}`, nil)
+
+	assert.Equal(t, "-1 true false", output)
+}
+
+func TestTemplateGavPath(t *testing.T) {
+	output := renderTestTemplate(t,
+		`{{ range .Artifacts }}{{ gavPath .GroupId .ArtifactId .Version }} {{ end }}`, nil)
+
+	assert.Equal(t, "dev/galasa/dev.galasa.api/0.36.0 org/example/ext.isolated/1.0.0 org/example/ext.other/2.0.0 ", output)
+}
+
+func TestTemplateCanIncludePartials(t *testing.T) {
+	partials := map[string]string{
+		"dependency": `<dependency>{{ .ArtifactId }}</dependency>`,
+	}
+	output := renderTestTemplate(t, `{{ range .Artifacts }}{{ template "dependency" . }}{{ end }}`, partials)
+
+	assert.Equal(t, "<dependency>dev.galasa.api</dependency><dependency>ext.isolated</dependency><dependency>ext.other</dependency>", output)
+}
+
+func TestCanLoadTemplatePartialsFromFolder(t *testing.T) {
+	fs := utils.NewOverridableMockFileSystem()
+	fs.WriteTextFile("/templates/dependency.template", "<dependency/>")
+	fs.WriteTextFile("/templates/header.tmpl", "<header/>")
+
+	partials, err := LoadTemplatePartials(fs, "/templates")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"dependency": "<dependency/>", "header": "<header/>"}, partials)
+}
@@ -0,0 +1,291 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const templateTestFramework = `apiVersion: galasa.dev/v1alpha
+kind: Release
+release:
+  version: 0.36.0
+framework:
+  bundles:
+  - artifact: dev.galasa.framework
+    version: 0.36.0
+    obr: true
+    bom: true
+    javadoc: true
+  - artifact: galasa-boot
+    version: 0.36.1
+api:
+  bundles:
+  - artifact: dev.galasa.api
+    version: 0.36.0
+    mvp: true
+    managerdoc: true
+`
+
+const templateTestManagers = `apiVersion: galasa.dev/v1alpha
+kind: Release
+managers:
+  bundles:
+  - artifact: dev.galasa.zos.manager
+    version: 0.36.0
+    obr: true
+    codecoverage: true
+external:
+  bundles:
+  - group: org.example
+    artifact: ext.isolated
+    version: 1.0.0
+    isolated: true
+    bom: true
+  - group: org.example
+    artifact: ext.other
+    version: 2.0.0
+`
+
+func createTemplateFs() *utils.MockFileSystem {
+	fs := utils.NewOverridableMockFileSystem()
+	fs.WriteTextFile("/framework.yaml", templateTestFramework)
+	fs.WriteTextFile("/managers.yaml", templateTestManagers)
+	fs.WriteTextFile("/list.template", `{{ .Release }} {{ .BootRelease }}:{{ range .Artifacts }} {{ .ArtifactId }}{{ end }}`)
+	return fs
+}
+
+func createTemplateOptions() TemplateOptions {
+	return TemplateOptions{
+		ReleaseMetadataFilePaths: []string{"/framework.yaml", "/managers.yaml"},
+		MergeStrategy:            "last-wins",
+		TemplateFilePath:         "/list.template",
+		OutputFilePath:           "/output.txt",
+	}
+}
+
+func renderWithOptions(t *testing.T, options TemplateOptions) string {
+	fs := createTemplateFs()
+	var stdout, progress bytes.Buffer
+	err := TemplateExecute(fs, options, &stdout, &progress)
+	assert.Nil(t, err)
+
+	output, _ := fs.ReadTextFile(options.OutputFilePath)
+	return output
+}
+
+func TestTemplateWithEachArtifactTypePreset(t *testing.T) {
+	expectedOutputs := map[string]string{
+		"obr":          "0.36.0 0.36.1: dev.galasa.framework dev.galasa.zos.manager",
+		"bom":          "0.36.0 0.36.1: dev.galasa.framework ext.isolated",
+		"mvp":          "0.36.0 0.36.1: dev.galasa.api",
+		"isolated":     "0.36.0 0.36.1: dev.galasa.framework galasa-boot dev.galasa.api dev.galasa.zos.manager ext.isolated",
+		"javadoc":      "0.36.0 0.36.1: dev.galasa.framework dev.galasa.zos.manager",
+		"managerdoc":   "0.36.0 0.36.1: dev.galasa.api dev.galasa.zos.manager",
+		"codecoverage": "0.36.0 0.36.1: dev.galasa.zos.manager",
+	}
+
+	for _, presetName := range GetSelectorPresetNames() {
+		options := createTemplateOptions()
+		options.Presets = []string{presetName}
+
+		assert.Equal(t, expectedOutputs[presetName], renderWithOptions(t, options), presetName)
+	}
+}
+
+func TestTemplateWithSelectExpression(t *testing.T) {
+	options := createTemplateOptions()
+	options.Selector = "section == external || artifact == galasa-boot"
+
+	assert.Equal(t, "0.36.0 0.36.1: galasa-boot ext.isolated ext.other", renderWithOptions(t, options))
+}
+
+func TestTemplateWithManifest(t *testing.T) {
+	fs := createTemplateFs()
+	fs.WriteTextFile("/manifest.yaml", `- template: /list.template
+  output: /obr.txt
+  preset: obr
+- template: /list.template
+  output: /api.txt
+  selector: section == api
+`)
+	options := createTemplateOptions()
+	options.TemplateFilePath = ""
+	options.OutputFilePath = ""
+	options.ManifestFilePath = "/manifest.yaml"
+
+	var stdout, progress bytes.Buffer
+	err := TemplateExecute(fs, options, &stdout, &progress)
+	assert.Nil(t, err)
+
+	obr, _ := fs.ReadTextFile("/obr.txt")
+	assert.Equal(t, "0.36.0 0.36.1: dev.galasa.framework dev.galasa.zos.manager", obr)
+	api, _ := fs.ReadTextFile("/api.txt")
+	assert.Equal(t, "0.36.0 0.36.1: dev.galasa.api", api)
+}
+
+func TestTemplateSelectionMistakesAreErrors(t *testing.T) {
+	noSelection := createTemplateOptions()
+
+	twoPresets := createTemplateOptions()
+	twoPresets.Presets = []string{"obr", "bom"}
+
+	selectAndPreset := createTemplateOptions()
+	selectAndPreset.Presets = []string{"obr"}
+	selectAndPreset.Selector = "bom"
+
+	badExpression := createTemplateOptions()
+	badExpression.Selector = "bom &&"
+
+	manifestAndTemplate := createTemplateOptions()
+	manifestAndTemplate.ManifestFilePath = "/manifest.yaml"
+
+	noOutput := createTemplateOptions()
+	noOutput.Presets = []string{"obr"}
+	noOutput.OutputFilePath = ""
+
+	for name, options := range map[string]TemplateOptions{
+		"no selection":          noSelection,
+		"two presets":           twoPresets,
+		"select and preset":     selectAndPreset,
+		"bad expression":        badExpression,
+		"manifest and template": manifestAndTemplate,
+		"no output":             noOutput,
+	} {
+		fs := createTemplateFs()
+		var stdout, progress bytes.Buffer
+		err := TemplateExecute(fs, options, &stdout, &progress)
+		assert.NotNil(t, err, name)
+
+		isWritten, _ := fs.Exists("/output.txt")
+		assert.False(t, isWritten, name)
+	}
+}
+
+func TestTemplateFailsIfReleaseHasNoVersion(t *testing.T) {
+	options := createTemplateOptions()
+	options.ReleaseMetadataFilePaths = []string{"/managers.yaml"}
+	options.Presets = []string{"obr"}
+
+	var stdout, progress bytes.Buffer
+	err := TemplateExecute(createTemplateFs(), options, &stdout, &progress)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "release version")
+}
+
+func TestTemplateFailsIfReleaseFileIsMissing(t *testing.T) {
+	options := createTemplateOptions()
+	options.ReleaseMetadataFilePaths = []string{"/missing.yaml"}
+	options.Presets = []string{"obr"}
+
+	var stdout, progress bytes.Buffer
+	err := TemplateExecute(createTemplateFs(), options, &stdout, &progress)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "/missing.yaml")
+}
+
+func TestTemplateFailsIfOutputCannotBeWritten(t *testing.T) {
+	fs := createTemplateFs()
+	fs.VirtualFunction_WriteBinaryFile = func(targetFilePath string, desiredContents []byte) error {
+		return errors.New("simulated failure")
+	}
+	options := createTemplateOptions()
+	options.Presets = []string{"obr"}
+
+	var stdout, progress bytes.Buffer
+	err := TemplateExecute(fs, options, &stdout, &progress)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "failed to write output file /output.txt")
+}
+
+func TestTemplateOutputDashWritesToStdout(t *testing.T) {
+	fs := createTemplateFs()
+	options := createTemplateOptions()
+	options.OutputFilePath = "-"
+	options.Presets = []string{"obr"}
+	var stdout, progress bytes.Buffer
+
+	err := TemplateExecute(fs, options, &stdout, &progress)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "0.36.0 0.36.1: dev.galasa.framework dev.galasa.zos.manager", stdout.String())
+	assert.Contains(t, progress.String(), "Artifacts selected by expression: obr")
+	isWritten, _ := fs.Exists("-")
+	assert.False(t, isWritten)
+}
+
+func TestTemplateCheckPassesIfOutputIsUpToDate(t *testing.T) {
+	fs := createTemplateFs()
+	fs.WriteTextFile("/output.txt", "0.36.0 0.36.1: dev.galasa.framework dev.galasa.zos.manager")
+	options := createTemplateOptions()
+	options.Presets = []string{"obr"}
+	options.IsCheckMode = true
+	var stdout, progress bytes.Buffer
+
+	err := TemplateExecute(fs, options, &stdout, &progress)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "", stdout.String())
+	assert.Contains(t, progress.String(), "1 output files are up to date")
+}
+
+func TestTemplateCheckFailsWithDiffIfOutputIsOutOfDate(t *testing.T) {
+	fs := createTemplateFs()
+	fs.WriteTextFile("/list.template", "{{ range .Artifacts }}{{ .ArtifactId }}\n{{ end }}")
+	fs.WriteTextFile("/output.txt", "dev.galasa.framework\ndev.galasa.old.manager\n")
+	options := createTemplateOptions()
+	options.Presets = []string{"obr"}
+	options.IsCheckMode = true
+	var stdout, progress bytes.Buffer
+
+	err := TemplateExecute(fs, options, &stdout, &progress)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "1 of 1 output files are out of date")
+	assert.Equal(t, "--- /output.txt\n"+
+		"+++ /output.txt (rendered from /list.template)\n"+
+		"@@ -1,2 +1,2 @@\n"+
+		" dev.galasa.framework\n"+
+		"-dev.galasa.old.manager\n"+
+		"+dev.galasa.zos.manager\n", stdout.String())
+
+	output, _ := fs.ReadTextFile("/output.txt")
+	assert.Equal(t, "dev.galasa.framework\ndev.galasa.old.manager\n", output, "check mode must not change the output file")
+}
+
+func TestTemplateCheckFailsIfOutputIsMissing(t *testing.T) {
+	fs := createTemplateFs()
+	options := createTemplateOptions()
+	options.Presets = []string{"obr"}
+	options.IsCheckMode = true
+	var stdout, progress bytes.Buffer
+
+	err := TemplateExecute(fs, options, &stdout, &progress)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, stdout.String(), "@@ -0,0 +1 @@")
+	isWritten, _ := fs.Exists("/output.txt")
+	assert.False(t, isWritten)
+}
+
+func TestTemplateCheckCannotBeUsedWithStdout(t *testing.T) {
+	options := createTemplateOptions()
+	options.OutputFilePath = "-"
+	options.Presets = []string{"obr"}
+	options.IsCheckMode = true
+
+	var stdout, progress bytes.Buffer
+	err := TemplateExecute(createTemplateFs(), options, &stdout, &progress)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "--check")
+}
@@ -0,0 +1,255 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"galasa.dev/buildUtilities/pkg/galasayaml"
+	"galasa.dev/buildUtilities/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Versions such as 0.36.0, 1.2, 0.36.0-SNAPSHOT, 32.1.2-jre or 2.0.0.Final
+var validVersionRegex = regexp.MustCompile(`^[0-9]+(\.[0-9]+){1,3}([-.+_]?[0-9A-Za-z][0-9A-Za-z.+_-]*)?$`)
+
+// The fields which may be used at each level of a release file.
+var (
+	releaseFileFields  = []string{"apiVersion", "kind", "metadata", "release", "framework", "api", "managers", "external"}
+	metadataFields     = []string{"name"}
+	releaseFields      = []string{"version"}
+	sectionFields      = []string{"bundles"}
+	bundleFields       = []string{"group", "artifact", "version", "type", "obr", "bom", "isolated", "mvp", "javadoc", "managerdoc", "codecoverage"}
+	bundleStringFields = []string{"group", "artifact", "version", "type"}
+)
+
+// Something wrong with a release file, and where it is.
+type ValidationProblem struct {
+	FilePath string
+	Line     int
+	Message  string
+}
+
+func (problem ValidationProblem) String() string {
+	return fmt.Sprintf("%s:%d: %s", problem.FilePath, problem.Line, problem.Message)
+}
+
+// ValidateExecute checks that each release metadata file is well formed, and prints each problem found.
+// An error is returned if any file has a problem.
+func ValidateExecute(fs utils.FileSystem, releaseFilePaths []string) error {
+	var err error
+	var problems []ValidationProblem
+
+	for _, filePath := range releaseFilePaths {
+		var contents string
+		contents, err = fs.ReadTextFile(filePath)
+		if err != nil {
+			break
+		}
+		problems = append(problems, validateReleaseFile(filePath, contents)...)
+	}
+
+	if err == nil {
+		printValidationProblems(os.Stdout, problems, len(releaseFilePaths))
+		if len(problems) > 0 {
+			err = fmt.Errorf("%d release metadata problems found", len(problems))
+		}
+	}
+
+	return err
+}
+
+func printValidationProblems(writer io.Writer, problems []ValidationProblem, fileCount int) {
+	for _, problem := range problems {
+		fmt.Fprintln(writer, problem.String())
+	}
+	fmt.Fprintf(writer, "%d files checked, %d problems found\n", fileCount, len(problems))
+}
+
+// releaseValidator collects the problems found in one release file.
+type releaseValidator struct {
+	filePath string
+	problems []ValidationProblem
+	// Where each group:artifact was first seen, so duplicates can be reported.
+	bundleLines map[string]int
+}
+
+func validateReleaseFile(filePath string, contents string) []ValidationProblem {
+	validator := releaseValidator{filePath: filePath, bundleLines: make(map[string]int)}
+
+	var document yaml.Node
+	err := yaml.Unmarshal([]byte(contents), &document)
+	if err != nil {
+		validator.addProblem(findYamlErrorLine(err), err.Error())
+	} else if len(document.Content) == 0 {
+		validator.addProblem(1, "the file is empty")
+	} else {
+		validator.validateReleaseNode(document.Content[0])
+	}
+
+	return validator.problems
+}
+
+func (validator *releaseValidator) addProblem(line int, format string, args ...interface{}) {
+	validator.problems = append(validator.problems, ValidationProblem{
+		FilePath: validator.filePath,
+		Line:     line,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (validator *releaseValidator) validateReleaseNode(node *yaml.Node) {
+	if validator.checkIsMapping(node, "the release") {
+		fields := validator.checkFields(node, releaseFileFields, "the release")
+
+		validator.checkStringValue(node, fields, "apiVersion", DEFAULT_API_VERSION)
+		validator.checkStringValue(node, fields, "kind", DEFAULT_KIND)
+
+		if metadataNode, isFound := fields["metadata"]; isFound && validator.checkIsMapping(metadataNode, "metadata") {
+			validator.checkFields(metadataNode, metadataFields, "metadata")
+		}
+
+		if releaseNode, isFound := fields["release"]; isFound && validator.checkIsMapping(releaseNode, "release") {
+			releaseFieldNodes := validator.checkFields(releaseNode, releaseFields, "release")
+			if versionNode, isFound := releaseFieldNodes["version"]; isFound {
+				validator.checkVersion(versionNode, "release version")
+			}
+		}
+
+		for _, sectionName := range galasayaml.SectionNames {
+			if sectionNode, isFound := fields[sectionName]; isFound {
+				validator.validateSectionNode(sectionNode, sectionName)
+			}
+		}
+	}
+}
+
+func (validator *releaseValidator) validateSectionNode(node *yaml.Node, sectionName string) {
+	if validator.checkIsMapping(node, "the "+sectionName+" section") {
+		fields := validator.checkFields(node, sectionFields, "the "+sectionName+" section")
+		bundlesNode, isFound := fields["bundles"]
+		if isFound {
+			if bundlesNode.Kind != yaml.SequenceNode {
+				validator.addProblem(bundlesNode.Line, "the bundles of the %s section should be a list", sectionName)
+			} else {
+				for _, bundleNode := range bundlesNode.Content {
+					validator.validateBundleNode(bundleNode, sectionName)
+				}
+			}
+		}
+	}
+}
+
+func (validator *releaseValidator) validateBundleNode(node *yaml.Node, sectionName string) {
+	if validator.checkIsMapping(node, "a bundle in the "+sectionName+" section") {
+		fields := validator.checkFields(node, bundleFields, "a bundle")
+
+		for _, requiredField := range []string{"artifact", "version"} {
+			if _, isFound := fields[requiredField]; !isFound {
+				validator.addProblem(node.Line, "a bundle in the %s section has no %s", sectionName, requiredField)
+			}
+		}
+
+		for _, fieldName := range bundleStringFields {
+			if fieldNode, isFound := fields[fieldName]; isFound && fieldNode.Kind != yaml.ScalarNode {
+				validator.addProblem(fieldNode.Line, "the bundle %s should be a single value", fieldName)
+			}
+		}
+
+		if versionNode, isFound := fields["version"]; isFound {
+			validator.checkVersion(versionNode, "bundle version")
+		}
+
+		// The flags must be true or false.
+		var bundle galasayaml.Bundle
+		err := node.Decode(&bundle)
+		if err != nil {
+			validator.addProblem(node.Line, "the bundle could not be read: %s", strings.TrimPrefix(err.Error(), "yaml: "))
+		} else if bundle.Artifact != "" {
+			key := bundle.GetKey()
+			firstLine, isDuplicate := validator.bundleLines[key]
+			if isDuplicate {
+				validator.addProblem(node.Line, "bundle %s is a duplicate of the bundle on line %d", key, firstLine)
+			} else {
+				validator.bundleLines[key] = node.Line
+			}
+		}
+	}
+}
+
+func (validator *releaseValidator) checkIsMapping(node *yaml.Node, description string) bool {
+	isMapping := node.Kind == yaml.MappingNode
+	if !isMapping {
+		validator.addProblem(node.Line, "%s should be a set of fields", description)
+	}
+	return isMapping
+}
+
+// checkFields reports any unknown or repeated fields in a mapping, and returns the value node of each field.
+func (validator *releaseValidator) checkFields(node *yaml.Node, knownFields []string, description string) map[string]*yaml.Node {
+	fields := make(map[string]*yaml.Node)
+
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		keyNode := node.Content[index]
+		valueNode := node.Content[index+1]
+		fieldName := keyNode.Value
+
+		if !isKnownField(fieldName, knownFields) {
+			validator.addProblem(keyNode.Line, "unknown field '%s' in %s, expected one of %s",
+				fieldName, description, strings.Join(knownFields, ", "))
+		} else if _, isRepeated := fields[fieldName]; isRepeated {
+			validator.addProblem(keyNode.Line, "field '%s' is repeated in %s", fieldName, description)
+		} else {
+			fields[fieldName] = valueNode
+		}
+	}
+
+	return fields
+}
+
+func isKnownField(fieldName string, knownFields []string) bool {
+	isKnown := false
+	for _, knownField := range knownFields {
+		if fieldName == knownField {
+			isKnown = true
+			break
+		}
+	}
+	return isKnown
+}
+
+func (validator *releaseValidator) checkStringValue(node *yaml.Node, fields map[string]*yaml.Node, fieldName string, expectedValue string) {
+	valueNode, isFound := fields[fieldName]
+	if !isFound {
+		validator.addProblem(node.Line, "%s is missing, it should be %s", fieldName, expectedValue)
+	} else if valueNode.Value != expectedValue {
+		validator.addProblem(valueNode.Line, "%s is '%s', it should be %s", fieldName, valueNode.Value, expectedValue)
+	}
+}
+
+func (validator *releaseValidator) checkVersion(node *yaml.Node, description string) {
+	if node.Kind == yaml.ScalarNode && !validVersionRegex.MatchString(node.Value) {
+		validator.addProblem(node.Line, "%s '%s' is not a valid version", description, node.Value)
+	}
+}
+
+var yamlErrorLineRegex = regexp.MustCompile(`line ([0-9]+)`)
+
+// findYamlErrorLine gets the line number from a yaml syntax error, or 1 if it doesn't have one.
+func findYamlErrorLine(err error) int {
+	line := 1
+	match := yamlErrorLineRegex.FindStringSubmatch(err.Error())
+	if match != nil {
+		line, _ = strconv.Atoi(match[1])
+	}
+	return line
+}
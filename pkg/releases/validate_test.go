@@ -0,0 +1,123 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package releases
+
+import (
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const validReleaseFileContents = `apiVersion: galasa.dev/v1alpha
+kind: Release
+metadata:
+  name: galasa-release
+release:
+  version: 0.36.0
+framework:
+  bundles:
+  - artifact: dev.galasa.framework
+    version: 0.36.0-SNAPSHOT
+    obr: true
+external:
+  bundles:
+  - group: com.google.guava
+    artifact: guava
+    version: 32.1.2-jre
+    isolated: true
+`
+
+func getProblemStrings(problems []ValidationProblem) []string {
+	messages := []string{}
+	for _, problem := range problems {
+		messages = append(messages, problem.String())
+	}
+	return messages
+}
+
+func TestValidReleaseFileHasNoProblems(t *testing.T) {
+	problems := validateReleaseFile("release.yaml", validReleaseFileContents)
+	assert.Empty(t, problems)
+}
+
+func TestValidateReportsUnknownFieldsWithLineNumbers(t *testing.T) {
+	problems := validateReleaseFile("release.yaml", `apiVersion: galasa.dev/v1alpha
+kind: Release
+managers:
+  bundles:
+  - artifact: dev.galasa.zos.manager
+    version: 0.36.0
+    javdoc: true
+frameworks: {}
+`)
+
+	assert.Equal(t, []string{
+		"release.yaml:8: unknown field 'frameworks' in the release, expected one of " +
+			"apiVersion, kind, metadata, release, framework, api, managers, external",
+		"release.yaml:7: unknown field 'javdoc' in a bundle, expected one of " +
+			"group, artifact, version, type, obr, bom, isolated, mvp, javadoc, managerdoc, codecoverage",
+	}, getProblemStrings(problems))
+}
+
+func TestValidateChecksApiVersionAndKind(t *testing.T) {
+	problems := validateReleaseFile("release.yaml", `apiVersion: galasa.dev/v2
+release:
+  version: 0.36.0
+`)
+
+	assert.Equal(t, []string{
+		"release.yaml:1: apiVersion is 'galasa.dev/v2', it should be galasa.dev/v1alpha",
+		"release.yaml:1: kind is missing, it should be Release",
+	}, getProblemStrings(problems))
+}
+
+func TestValidateChecksBundles(t *testing.T) {
+	problems := validateReleaseFile("release.yaml", `apiVersion: galasa.dev/v1alpha
+kind: Release
+framework:
+  bundles:
+  - artifact: dev.galasa.framework
+  - artifact: dev.galasa.api
+    version: latest
+  - artifact: dev.galasa.framework
+    group: dev.galasa
+    version: 0.36.0
+  - artifact: dev.galasa.other
+    version: 0.36.0
+    obr: maybe
+`)
+
+	assert.Equal(t, []string{
+		"release.yaml:5: a bundle in the framework section has no version",
+		"release.yaml:7: bundle version 'latest' is not a valid version",
+		"release.yaml:8: bundle dev.galasa:dev.galasa.framework is a duplicate of the bundle on line 5",
+	}, getProblemStrings(problems)[:3])
+	assert.Len(t, problems, 4)
+	assert.Equal(t, 11, problems[3].Line)
+	assert.Contains(t, problems[3].Message, "the bundle could not be read")
+}
+
+func TestValidateReportsYamlSyntaxErrors(t *testing.T) {
+	problems := validateReleaseFile("release.yaml", "apiVersion: galasa.dev/v1alpha\nkind: [Release\n")
+
+	assert.Len(t, problems, 1)
+	assert.Equal(t, "release.yaml", problems[0].FilePath)
+}
+
+func TestValidateExecuteFailsIfAnyFileHasProblems(t *testing.T) {
+	fs := utils.NewOverridableMockFileSystem()
+	fs.WriteTextFile("/good.yaml", validReleaseFileContents)
+	fs.WriteTextFile("/bad.yaml", "kind: Release\n")
+
+	err := ValidateExecute(fs, []string{"/good.yaml"})
+	assert.Nil(t, err)
+
+	err = ValidateExecute(fs, []string{"/good.yaml", "/bad.yaml"})
+	assert.NotNil(t, err)
+	assert.Equal(t, "1 release metadata problems found", err.Error())
+}
@@ -5,6 +5,8 @@
  */
 package utils
 
+import "io"
+
 // ------------------------------------------------------------------------------------
 // The implementation of the file read closer interface.
 // -----------------------------------------------------------------------------------
@@ -56,6 +58,13 @@ func (mockFile *MockFile) mockFileClose() error {
 }
 
 func (mockFile *MockFile) mockFileRead(data []byte) (int, error) {
-    copy(data, mockFile.data)
-    return len(mockFile.data), mockFile.err
+    if len(mockFile.data) == 0 {
+        if mockFile.err != nil {
+            return 0, mockFile.err
+        }
+        return 0, io.EOF
+    }
+    count := copy(data, mockFile.data)
+    mockFile.data = mockFile.data[count:]
+    return count, nil
 }
\ No newline at end of file
@@ -40,8 +40,6 @@ type MockFileSystem struct {
 
 	filePathSeparator string
 
-	fileReadCloser *MockFile
-
 	// The mock struct contains methods which can be over-ridden on a per-test basis.
 	VirtualFunction_MkdirAll             func(targetFolderPath string) error
 	VirtualFunction_WriteTextFile        func(targetFilePath string, desiredContents string) error
@@ -81,8 +79,6 @@ func NewOverridableMockFileSystem() *MockFileSystem {
 
 	mockFileSystem.filePathSeparator = "/"
 
-	mockFileSystem.fileReadCloser = NewOverridableMockFile()
-
 	// Set up functions inside the structure to call the basic/default mock versions...
 	// These can later be over-ridden on a test-by-test basis.
 	mockFileSystem.VirtualFunction_MkdirAll = func(targetFolderPath string) error {
@@ -331,8 +327,15 @@ func mockFSReadDir(fs MockFileSystem, dirPath string) ([]os.DirEntry, error) {
 }
 
 func mockFSOpenFile(fs MockFileSystem, filePath string) (io.ReadCloser, error) {
-	fs.fileReadCloser.data = []byte("dummy data")
-	return fs.fileReadCloser, nil
+	// Each file which is opened gets its own reader, so it can be read at the same time as others.
+	node := fs.data[filePath]
+	if node == nil || node.isDir {
+		return nil, os.ErrNotExist
+	}
+
+	file := NewOverridableMockFile()
+	file.data = node.content
+	return file, nil
 }
 
 func mockFSWalkDir(fs MockFileSystem, dirPath string, walkDirFunc fs.WalkDirFunc) error {
@@ -0,0 +1,207 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The number of unchanged lines shown around each change in a unified diff.
+const UNIFIED_DIFF_CONTEXT_LINES = 3
+
+type diffLine struct {
+	// ' ' for an unchanged line, '-' for a removed line, '+' for an added line.
+	kind byte
+	// The text of the line, including its line ending if it has one.
+	text string
+}
+
+// UnifiedDiff returns the differences between two texts in the unified diff format used by
+// `diff -u`, or "" if they are the same.
+func UnifiedDiff(fromName string, toName string, fromText string, toText string) string {
+	var diff strings.Builder
+
+	if fromText != toText {
+		lines := diffLines(splitLinesKeepingEndings(fromText), splitLinesKeepingEndings(toText))
+
+		fmt.Fprintf(&diff, "--- %s\n+++ %s\n", fromName, toName)
+
+		index := 0
+		for index < len(lines) {
+			// Find the next change.
+			for index < len(lines) && lines[index].kind == ' ' {
+				index++
+			}
+			if index >= len(lines) {
+				break
+			}
+
+			// Join together changes which are close enough for their context lines to overlap.
+			hunkStart := maxInt(0, index-UNIFIED_DIFF_CONTEXT_LINES)
+			changeEnd := index
+			for {
+				for changeEnd < len(lines) && lines[changeEnd].kind != ' ' {
+					changeEnd++
+				}
+				nextChange := changeEnd
+				for nextChange < len(lines) && lines[nextChange].kind == ' ' {
+					nextChange++
+				}
+				if nextChange < len(lines) && nextChange-changeEnd <= 2*UNIFIED_DIFF_CONTEXT_LINES {
+					changeEnd = nextChange
+				} else {
+					break
+				}
+			}
+			hunkEnd := minInt(len(lines), changeEnd+UNIFIED_DIFF_CONTEXT_LINES)
+
+			writeDiffHunk(&diff, lines, hunkStart, hunkEnd)
+			index = hunkEnd
+		}
+	}
+
+	return diff.String()
+}
+
+func writeDiffHunk(diff *strings.Builder, lines []diffLine, hunkStart int, hunkEnd int) {
+	fromStart, toStart := 1, 1
+	for _, line := range lines[:hunkStart] {
+		if line.kind != '+' {
+			fromStart++
+		}
+		if line.kind != '-' {
+			toStart++
+		}
+	}
+
+	fromCount, toCount := 0, 0
+	for _, line := range lines[hunkStart:hunkEnd] {
+		if line.kind != '+' {
+			fromCount++
+		}
+		if line.kind != '-' {
+			toCount++
+		}
+	}
+
+	// An empty range is numbered by the line before it.
+	if fromCount == 0 {
+		fromStart--
+	}
+	if toCount == 0 {
+		toStart--
+	}
+
+	fmt.Fprintf(diff, "@@ -%s +%s @@\n", formatDiffRange(fromStart, fromCount), formatDiffRange(toStart, toCount))
+	for _, line := range lines[hunkStart:hunkEnd] {
+		diff.WriteByte(line.kind)
+		diff.WriteString(line.text)
+		if !strings.HasSuffix(line.text, "\n") {
+			diff.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// formatDiffRange leaves out the number of lines when there is only one, as `diff -u` does.
+func formatDiffRange(start int, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLinesKeepingEndings(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines works out which lines were removed and added to turn one list of lines into
+// the other, keeping as many lines unchanged as possible.
+func diffLines(fromLines []string, toLines []string) []diffLine {
+	var lines []diffLine
+
+	// The start and end of most files which are compared are the same, so only the middle
+	// needs the more expensive comparison.
+	prefixLength := 0
+	for prefixLength < len(fromLines) && prefixLength < len(toLines) && fromLines[prefixLength] == toLines[prefixLength] {
+		prefixLength++
+	}
+	suffixLength := 0
+	for suffixLength < len(fromLines)-prefixLength && suffixLength < len(toLines)-prefixLength &&
+		fromLines[len(fromLines)-1-suffixLength] == toLines[len(toLines)-1-suffixLength] {
+		suffixLength++
+	}
+
+	for _, line := range fromLines[:prefixLength] {
+		lines = append(lines, diffLine{kind: ' ', text: line})
+	}
+
+	lines = append(lines, diffLinesByLongestCommonSubsequence(
+		fromLines[prefixLength:len(fromLines)-suffixLength],
+		toLines[prefixLength:len(toLines)-suffixLength])...)
+
+	for _, line := range fromLines[len(fromLines)-suffixLength:] {
+		lines = append(lines, diffLine{kind: ' ', text: line})
+	}
+
+	return lines
+}
+
+func diffLinesByLongestCommonSubsequence(fromLines []string, toLines []string) []diffLine {
+	var lines []diffLine
+
+	// commonLengths[i][j] is the length of the longest common subsequence of fromLines[i:] and toLines[j:]
+	commonLengths := make([][]int32, len(fromLines)+1)
+	for i := range commonLengths {
+		commonLengths[i] = make([]int32, len(toLines)+1)
+	}
+	for i := len(fromLines) - 1; i >= 0; i-- {
+		for j := len(toLines) - 1; j >= 0; j-- {
+			if fromLines[i] == toLines[j] {
+				commonLengths[i][j] = commonLengths[i+1][j+1] + 1
+			} else if commonLengths[i+1][j] >= commonLengths[i][j+1] {
+				commonLengths[i][j] = commonLengths[i+1][j]
+			} else {
+				commonLengths[i][j] = commonLengths[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(fromLines) || j < len(toLines) {
+		if i < len(fromLines) && j < len(toLines) && fromLines[i] == toLines[j] {
+			lines = append(lines, diffLine{kind: ' ', text: fromLines[i]})
+			i++
+			j++
+		} else if j >= len(toLines) || (i < len(fromLines) && commonLengths[i+1][j] >= commonLengths[i][j+1]) {
+			lines = append(lines, diffLine{kind: '-', text: fromLines[i]})
+			i++
+		} else {
+			lines = append(lines, diffLine{kind: '+', text: toLines[j]})
+			j++
+		}
+	}
+
+	return lines
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiffOfSameTextIsEmpty(t *testing.T) {
+	assert.Equal(t, "", UnifiedDiff("a", "b", "x\ny\n", "x\ny\n"))
+}
+
+func TestUnifiedDiffShowsChangeWithContext(t *testing.T) {
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	to := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n"
+
+	assert.Equal(t, "--- old\n+++ new\n"+
+		"@@ -2,7 +2,7 @@\n"+
+		" 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n", UnifiedDiff("old", "new", from, to))
+}
+
+func TestUnifiedDiffSplitsDistantChangesIntoHunks(t *testing.T) {
+	from := "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n"
+	to := "A\n1\n2\n3\n4\n5\n6\n7\n8\n"
+
+	assert.Equal(t, "--- old\n+++ new\n"+
+		"@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n"+
+		"@@ -7,4 +7,3 @@\n 6\n 7\n 8\n-b\n", UnifiedDiff("old", "new", from, to))
+}
+
+func TestUnifiedDiffJoinsNearbyChanges(t *testing.T) {
+	from := "a\n1\n2\n3\n4\n5\n6\nb\n"
+	to := "A\n1\n2\n3\n4\n5\n6\nB\n"
+
+	assert.Equal(t, "--- old\n+++ new\n"+
+		"@@ -1,8 +1,8 @@\n-a\n+A\n 1\n 2\n 3\n 4\n 5\n 6\n-b\n+B\n", UnifiedDiff("old", "new", from, to))
+}
+
+func TestUnifiedDiffFromEmptyText(t *testing.T) {
+	assert.Equal(t, "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+x\n+y\n", UnifiedDiff("old", "new", "", "x\ny\n"))
+}
+
+func TestUnifiedDiffShowsMissingNewlineAtEnd(t *testing.T) {
+	assert.Equal(t, "--- old\n+++ new\n@@ -1,2 +1,2 @@\n x\n-y\n+y\n\\ No newline at end of file\n",
+		UnifiedDiff("old", "new", "x\ny\n", "x\ny"))
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compares two versions such as 0.30.0 or 0.31.0-SNAPSHOT.
+// It returns a negative number if version1 is lower than version2, zero if they are equal,
+// or a positive number if version1 is higher.
+//
+// The base versions are compared number by number. If those are the same, then a version with
+// no suffix is higher than one with a suffix, as the suffixed version comes before the release.
+// Otherwise the suffixes are compared as text.
+func CompareVersions(version1 string, version2 string) int {
+	baseVersion1 := getBaseVersion(version1)
+	baseVersion2 := getBaseVersion(version2)
+
+	result := compareBaseVersions(baseVersion1, baseVersion2)
+	if result == 0 {
+		suffix1 := version1[len(baseVersion1):]
+		suffix2 := version2[len(baseVersion2):]
+
+		if suffix1 == suffix2 {
+			result = 0
+		} else if suffix1 == "" {
+			result = 1
+		} else if suffix2 == "" {
+			result = -1
+		} else {
+			result = strings.Compare(suffix1, suffix2)
+		}
+	}
+	return result
+}
+
+// The part of a version before any suffix, which starts with a - or _
+func getBaseVersion(version string) string {
+	baseVersion := version
+	index := strings.IndexAny(version, "-_")
+	if index != -1 {
+		baseVersion = version[:index]
+	}
+	return baseVersion
+}
+
+func compareBaseVersions(baseVersion1 string, baseVersion2 string) int {
+	result := 0
+	parts1 := strings.Split(baseVersion1, ".")
+	parts2 := strings.Split(baseVersion2, ".")
+
+	for index := 0; result == 0 && (index < len(parts1) || index < len(parts2)); index++ {
+		part1 := versionPartAt(parts1, index)
+		part2 := versionPartAt(parts2, index)
+
+		number1, err1 := strconv.Atoi(part1)
+		number2, err2 := strconv.Atoi(part2)
+		if err1 == nil && err2 == nil {
+			result = number1 - number2
+		} else {
+			// Parts which aren't numbers are compared as text.
+			result = strings.Compare(part1, part2)
+		}
+	}
+	return result
+}
+
+// Missing parts of a version count as zero, so 1.2 is the same as 1.2.0
+func versionPartAt(parts []string, index int) string {
+	part := "0"
+	if index < len(parts) {
+		part = parts[index]
+	}
+	return part
+}
@@ -0,0 +1,23 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, CompareVersions("0.30.0", "0.30.0"))
+	assert.Less(t, CompareVersions("0.30.0", "0.31.0"), 0)
+	assert.Greater(t, CompareVersions("0.30.10", "0.30.9"), 0)
+	assert.Greater(t, CompareVersions("1.0.0", "0.99.99"), 0)
+	assert.Equal(t, 0, CompareVersions("1.2", "1.2.0"))
+	assert.Less(t, CompareVersions("0.30.0-SNAPSHOT", "0.30.0"), 0)
+	assert.Greater(t, CompareVersions("0.30.0", "0.30.0-alpha"), 0)
+	assert.Less(t, CompareVersions("0.30.0-alpha", "0.30.0-beta"), 0)
+}
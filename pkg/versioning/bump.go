@@ -0,0 +1,189 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+const (
+	BUMP_PART_MAJOR = "major"
+	BUMP_PART_MINOR = "minor"
+	BUMP_PART_PATCH = "patch"
+)
+
+// A change to the version of a single module.
+type VersionChange struct {
+	Module     Module
+	OldVersion string
+	NewVersion string
+}
+
+// BumpExecute increments the major, minor or patch part of the version of every module in the
+// source folder, and changes any dependencies on those modules to the new versions. The existing
+// suffix of each version is kept, unless isReplacingSuffix is set, in which case the desiredSuffix
+// is used instead. A blank desiredSuffix removes the suffix.
+//
+// The files are changed in the same all-or-nothing way as SuffixSetExecute, with the original
+// contents written to the journal file. When isDryRun is set, the changes are printed but no files
+// are changed.
+func BumpExecute(
+	fs utils.FileSystem,
+	sourceCodeFolderPath string,
+	part string,
+	isReplacingSuffix bool,
+	desiredSuffix string,
+	isDryRun bool,
+	journalFilePath string,
+) error {
+	var err error
+
+	err = validateBumpPart(part)
+	if err == nil && isReplacingSuffix && desiredSuffix != "" {
+		err = validateSuffix(desiredSuffix)
+	}
+
+	if err == nil {
+		var modules []Module
+		modules, err = getModules(fs, sourceCodeFolderPath)
+		if err == nil {
+			var changes []VersionChange
+			changes, err = calculateBumpedVersions(modules, part, isReplacingSuffix, desiredSuffix)
+			if err == nil {
+				printVersionChanges(os.Stdout, changes)
+
+				if !isDryRun {
+					err = runTransaction(fs, journalFilePath, func(stagingFs utils.FileSystem) error {
+						err := applyVersionChanges(stagingFs, changes)
+						if err == nil {
+							err = setDependencyReferencesToVersionChanges(stagingFs, sourceCodeFolderPath, changes)
+						}
+						return err
+					})
+				}
+			}
+		}
+	}
+
+	return err
+}
+
+func validateBumpPart(part string) error {
+	var err error
+	switch part {
+	case BUMP_PART_MAJOR, BUMP_PART_MINOR, BUMP_PART_PATCH:
+		// It's valid.
+	default:
+		err = fmt.Errorf("Invalid version part '%s'. It must be one of '%s', '%s' or '%s'.",
+			part, BUMP_PART_MAJOR, BUMP_PART_MINOR, BUMP_PART_PATCH)
+	}
+	return err
+}
+
+func calculateBumpedVersions(modules []Module, part string, isReplacingSuffix bool, desiredSuffix string) ([]VersionChange, error) {
+	var err error
+	var changes []VersionChange = make([]VersionChange, 0)
+
+	for _, module := range modules {
+		currentVersion := module.GetVersion()
+
+		var newVersion string
+		newVersion, err = calculateBumpedVersion(currentVersion, part)
+		if err != nil {
+			err = fmt.Errorf("module %s cannot be bumped - %s", module.GetProjectName(), err.Error())
+			break
+		}
+
+		if isReplacingSuffix {
+			newVersion = calculateDesiredVersion(newVersion, desiredSuffix)
+		}
+
+		changes = append(changes, VersionChange{Module: module, OldVersion: currentVersion, NewVersion: newVersion})
+	}
+
+	return changes, err
+}
+
+// calculateBumpedVersion increments one part of an x.y.z version, resetting the less significant
+// parts to zero. Any suffix on the current version is kept.
+func calculateBumpedVersion(currentVersion string, part string) (string, error) {
+	var err error
+	var bumpedVersion string
+
+	baseVersion := calculateDesiredVersion(currentVersion, "")
+	suffix := currentVersion[len(baseVersion):]
+
+	var major, minor, patch int
+	major, minor, patch, err = parseBaseVersion(baseVersion)
+	if err == nil {
+		switch part {
+		case BUMP_PART_MAJOR:
+			major, minor, patch = major+1, 0, 0
+		case BUMP_PART_MINOR:
+			minor, patch = minor+1, 0
+		case BUMP_PART_PATCH:
+			patch = patch + 1
+		}
+		bumpedVersion = fmt.Sprintf("%d.%d.%d%s", major, minor, patch, suffix)
+	}
+
+	return bumpedVersion, err
+}
+
+// parseBaseVersion splits a version with no suffix into its major, minor and patch numbers.
+func parseBaseVersion(baseVersion string) (int, int, int, error) {
+	var err error
+	var numbers [3]int
+
+	parts := strings.Split(baseVersion, ".")
+	if len(parts) != 3 {
+		err = fmt.Errorf("version '%s' is not of the form x.y.z", baseVersion)
+	} else {
+		for index, part := range parts {
+			numbers[index], err = strconv.Atoi(part)
+			if err != nil || numbers[index] < 0 {
+				err = fmt.Errorf("version '%s' is not of the form x.y.z", baseVersion)
+				break
+			}
+		}
+	}
+
+	return numbers[0], numbers[1], numbers[2], err
+}
+
+func printVersionChanges(writer io.Writer, changes []VersionChange) {
+	for _, change := range changes {
+		fmt.Fprintf(writer, "%s %s -> %s\n", change.Module.GetProjectName(), change.OldVersion, change.NewVersion)
+	}
+}
+
+func applyVersionChanges(fs utils.FileSystem, changes []VersionChange) error {
+	var err error
+	for _, change := range changes {
+		err = substituteModuleVersion(fs, change.Module, change.NewVersion)
+		if err != nil {
+			break
+		}
+	}
+	return err
+}
+
+// setDependencyReferencesToVersionChanges changes any dependencies on the changed modules to refer
+// to their new versions.
+func setDependencyReferencesToVersionChanges(fs utils.FileSystem, sourceCodeFolderPath string, changes []VersionChange) error {
+	versionChanges := make(map[string]dependencyVersionChange)
+	for _, change := range changes {
+		addDependencyVersionChange(versionChanges, change.Module, change.NewVersion)
+	}
+	return substituteDependencyReferences(fs, sourceCodeFolderPath, versionChanges)
+}
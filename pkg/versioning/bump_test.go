@@ -0,0 +1,140 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanBumpPatchVersionKeepingSuffix(t *testing.T) {
+	newVersion, err := calculateBumpedVersion("0.36.1-SNAPSHOT", BUMP_PART_PATCH)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.36.2-SNAPSHOT", newVersion)
+}
+
+func TestCanBumpMinorVersionResettingPatch(t *testing.T) {
+	newVersion, err := calculateBumpedVersion("0.36.1", BUMP_PART_MINOR)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.37.0", newVersion)
+}
+
+func TestCanBumpMajorVersionResettingMinorAndPatch(t *testing.T) {
+	newVersion, err := calculateBumpedVersion("0.36.1_dev", BUMP_PART_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, "1.0.0_dev", newVersion)
+}
+
+func TestBumpFailsIfVersionIsNotThreeNumbers(t *testing.T) {
+	_, err := calculateBumpedVersion("0.36", BUMP_PART_PATCH)
+	assert.NotNil(t, err)
+
+	_, err = calculateBumpedVersion("0.x.1", BUMP_PART_PATCH)
+	assert.NotNil(t, err)
+}
+
+func TestBumpFailsIfPartIsInvalid(t *testing.T) {
+	err := BumpExecute(nil, "", "micro", false, "", false, "")
+	assert.NotNil(t, err)
+}
+
+func TestBumpFailsIfReplacementSuffixIsInvalid(t *testing.T) {
+	err := BumpExecute(nil, "", BUMP_PART_PATCH, true, "alpha", false, "")
+	assert.NotNil(t, err)
+}
+
+func TestCanBumpAllModulesKeepingSuffix(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	err := BumpExecute(mockFs, "/my", BUMP_PART_MINOR, false, "", false, "")
+	assert.Nil(t, err)
+
+	modules, err := getModules(mockFs, "/my")
+	assert.Nil(t, err)
+	assert.Len(t, modules, 2)
+	assert.Equal(t, "0.37.0-SNAPSHOT", modules[0].GetVersion())
+	assert.Equal(t, "0.37.0-dev", modules[1].GetVersion())
+}
+
+func TestCanBumpAllModulesReplacingSuffix(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	err := BumpExecute(mockFs, "/my", BUMP_PART_PATCH, true, "", false, "")
+	assert.Nil(t, err)
+
+	modules, err := getModules(mockFs, "/my")
+	assert.Nil(t, err)
+	assert.Len(t, modules, 2)
+	assert.Equal(t, "0.36.1", modules[0].GetVersion())
+	assert.Equal(t, "0.36.1", modules[1].GetVersion())
+}
+
+func TestBumpDryRunDoesNotChangeFiles(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	err := BumpExecute(mockFs, "/my", BUMP_PART_MAJOR, false, "", true, "")
+	assert.Nil(t, err)
+
+	modules, err := getModules(mockFs, "/my")
+	assert.Nil(t, err)
+	assert.Equal(t, "0.36.0-SNAPSHOT", modules[0].GetVersion())
+	assert.Equal(t, "0.36.0-dev", modules[1].GetVersion())
+}
+
+func TestBumpUpdatesReferencesToBumpedModules(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	mockFs.WriteTextFile("/my/random/folder/module3/build.gradle",
+		`version = "0.36.0-dev"
+dependencies {
+    implementation 'dev.galasa:my.random.folder.module1:0.36.0-SNAPSHOT'
+    implementation 'org.other:not.a.module:1.0.0'
+}`)
+
+	err := BumpExecute(mockFs, "/my", BUMP_PART_MINOR, false, "", false, "")
+	assert.Nil(t, err)
+
+	contents, _ := mockFs.ReadTextFile("/my/random/folder/module3/build.gradle")
+	assert.Equal(t, `version = "0.37.0-dev"
+dependencies {
+    implementation 'dev.galasa:my.random.folder.module1:0.37.0-SNAPSHOT'
+    implementation 'org.other:not.a.module:1.0.0'
+}`, contents)
+}
+
+func TestBumpWritesJournalAndCanBeUndone(t *testing.T) {
+	mockFs := createTwoModuleFs()
+
+	err := BumpExecute(mockFs, "/my", BUMP_PART_MAJOR, false, "", false, "/journal.json")
+	assert.Nil(t, err)
+
+	err = UndoExecute(mockFs, "/journal.json")
+	assert.Nil(t, err)
+
+	modules, _ := getModules(mockFs, "/my")
+	assert.Equal(t, "0.36.0-SNAPSHOT", modules[0].GetVersion())
+	assert.Equal(t, "0.36.0-dev", modules[1].GetVersion())
+}
+
+func TestBumpIsRolledBackIfWritingFailsPartWay(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	originalWriteTextFile := mockFs.VirtualFunction_WriteTextFile
+	mockFs.VirtualFunction_WriteTextFile = func(targetFilePath string, desiredContents string) error {
+		var err error
+		if targetFilePath == "/my/random/folder/module3/build.gradle" {
+			err = errors.New("simulated failure")
+		} else {
+			err = originalWriteTextFile(targetFilePath, desiredContents)
+		}
+		return err
+	}
+
+	err := BumpExecute(mockFs, "/my", BUMP_PART_MINOR, false, "", false, "/journal.json")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "rolled back")
+
+	contents, _ := mockFs.ReadTextFile("/my/random/folder/module1/build.gradle")
+	assert.Contains(t, contents, "0.36.0-SNAPSHOT")
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+// The rules which the module versions in a source tree are checked against.
+type CheckPolicy struct {
+	// All modules in the same folder must have the same suffix as each other.
+	IsSameSuffixRequired bool
+
+	// No module may have a -SNAPSHOT suffix.
+	IsSnapshotForbidden bool
+
+	// Modules may not have a version lower than the one given here for the same project name.
+	// Modules which aren't in the map are not checked.
+	MinimumVersions map[string]string
+}
+
+// A module version which breaks the check policy.
+type CheckViolation struct {
+	Module  Module
+	Message string
+}
+
+// CheckExecute checks the versions of all the modules in the source folder against the policy,
+// printing a report of any violations. An error is returned if there are any violations.
+func CheckExecute(fs utils.FileSystem, sourceCodeFolderPath string, policy CheckPolicy) error {
+	var err error
+
+	if !policy.IsSameSuffixRequired && !policy.IsSnapshotForbidden && policy.MinimumVersions == nil {
+		err = errors.New("No version policy has been given, so there is nothing to check.")
+	}
+
+	if err == nil {
+		var modules []Module
+		modules, err = getModules(fs, sourceCodeFolderPath)
+		if err == nil {
+			violations := checkModules(modules, policy)
+			printCheckViolations(os.Stdout, violations, len(modules))
+
+			if len(violations) > 0 {
+				err = fmt.Errorf("%d module version policy violations found", len(violations))
+			}
+		}
+	}
+
+	return err
+}
+
+func checkModules(modules []Module, policy CheckPolicy) []CheckViolation {
+	var violations []CheckViolation = make([]CheckViolation, 0)
+
+	var folderSuffixes map[string]folderSuffixUsage
+	if policy.IsSameSuffixRequired {
+		folderSuffixes = findFolderSuffixUsage(modules)
+	}
+
+	for _, module := range modules {
+		version := module.GetVersion()
+		suffix := getVersionSuffix(version)
+
+		if policy.IsSameSuffixRequired {
+			folder := getModuleFolder(module)
+			usage := folderSuffixes[folder]
+			if usage.isTied {
+				violations = append(violations, CheckViolation{Module: module,
+					Message: fmt.Sprintf("has suffix '%s' but the modules in %s don't agree on a suffix, they use '%s'",
+						suffix, folder, strings.Join(usage.suffixes, "', '"))})
+			} else if suffix != usage.mostCommonSuffix {
+				violations = append(violations, CheckViolation{Module: module,
+					Message: fmt.Sprintf("has suffix '%s' but the other modules in %s have suffix '%s'", suffix, folder, usage.mostCommonSuffix)})
+			}
+		}
+
+		if policy.IsSnapshotForbidden && strings.Contains(suffix, "SNAPSHOT") {
+			violations = append(violations, CheckViolation{Module: module,
+				Message: "has a SNAPSHOT version"})
+		}
+
+		minimumVersion, isFound := policy.MinimumVersions[module.GetArtifactName()]
+		if isFound && utils.CompareVersions(version, minimumVersion) < 0 {
+			violations = append(violations, CheckViolation{Module: module,
+				Message: fmt.Sprintf("has a version lower than the released version %s", minimumVersion)})
+		}
+	}
+
+	return violations
+}
+
+// The suffixes used by the modules in one folder.
+type folderSuffixUsage struct {
+	// The suffix used by more of the modules than any other.
+	mostCommonSuffix string
+
+	// More than one suffix is used by the highest number of modules, so there is no most common suffix.
+	isTied bool
+
+	// Every suffix used, in alphabetical order.
+	suffixes []string
+}
+
+func getVersionSuffix(version string) string {
+	return version[len(calculateDesiredVersion(version, "")):]
+}
+
+// getModuleFolder returns the folder which the module's own folder is in. Modules which are
+// next to each other in the source tree should share a suffix.
+func getModuleFolder(module Module) string {
+	return path.Dir(strings.TrimSuffix(module.GetPath(), "/"))
+}
+
+// findFolderSuffixUsage works out which suffix most modules in each folder use, so the odd ones out can be reported.
+func findFolderSuffixUsage(modules []Module) map[string]folderSuffixUsage {
+	suffixCountsByFolder := make(map[string]map[string]int)
+	for _, module := range modules {
+		folder := getModuleFolder(module)
+		if suffixCountsByFolder[folder] == nil {
+			suffixCountsByFolder[folder] = make(map[string]int)
+		}
+		suffixCountsByFolder[folder][getVersionSuffix(module.GetVersion())]++
+	}
+
+	usageByFolder := make(map[string]folderSuffixUsage)
+	for folder, suffixCounts := range suffixCountsByFolder {
+		var usage folderSuffixUsage
+		for suffix := range suffixCounts {
+			usage.suffixes = append(usage.suffixes, suffix)
+		}
+		sort.Strings(usage.suffixes)
+
+		highestCount := 0
+		for _, suffix := range usage.suffixes {
+			if suffixCounts[suffix] > highestCount {
+				usage.mostCommonSuffix = suffix
+				usage.isTied = false
+				highestCount = suffixCounts[suffix]
+			} else if suffixCounts[suffix] == highestCount {
+				usage.isTied = true
+			}
+		}
+		usageByFolder[folder] = usage
+	}
+	return usageByFolder
+}
+
+func printCheckViolations(writer io.Writer, violations []CheckViolation, moduleCount int) {
+	for _, violation := range violations {
+		fmt.Fprintf(writer, "%s %s %s (%s)\n", violation.Module.GetProjectName(), violation.Module.GetVersion(),
+			violation.Message, violation.Module.GetVersionFilePath())
+	}
+	fmt.Fprintf(writer, "%d modules checked, %d violations found\n", moduleCount, len(violations))
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFailsIfNoPolicyGiven(t *testing.T) {
+	err := CheckExecute(nil, "/my", CheckPolicy{})
+	assert.NotNil(t, err)
+}
+
+func TestCheckReportsModuleWithDifferentSuffix(t *testing.T) {
+	modules := []Module{
+		NewModule("a", "/a/", "0.30.0-alpha"),
+		NewModule("b", "/b/", "0.30.0-alpha"),
+		NewModule("c", "/c/", "0.30.0-SNAPSHOT"),
+	}
+	violations := checkModules(modules, CheckPolicy{IsSameSuffixRequired: true})
+
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "c", violations[0].Module.GetProjectName())
+	assert.Contains(t, violations[0].Message, "'-alpha'")
+}
+
+func TestCheckComparesSuffixesWithinEachFolder(t *testing.T) {
+	modules := []Module{
+		NewModule("a", "/framework/a/", "0.30.0-alpha"),
+		NewModule("b", "/framework/b/", "0.30.0-alpha"),
+		NewModule("c", "/framework/c/", "0.30.0-SNAPSHOT"),
+		NewModule("d", "/managers/d/", "0.30.0-beta"),
+		NewModule("e", "/managers/e/", "0.30.0-beta"),
+		NewModule("f", "/managers/f/", "0.30.0-beta"),
+		NewModule("g", "/managers/g/", "0.30.0-alpha"),
+	}
+	violations := checkModules(modules, CheckPolicy{IsSameSuffixRequired: true})
+
+	// The managers all having a different suffix to the framework isn't a violation.
+	assert.Len(t, violations, 2)
+	assert.Equal(t, "c", violations[0].Module.GetProjectName())
+	assert.Contains(t, violations[0].Message, "modules in /framework have suffix '-alpha'")
+	assert.Equal(t, "g", violations[1].Module.GetProjectName())
+	assert.Contains(t, violations[1].Message, "modules in /managers have suffix '-beta'")
+}
+
+func TestCheckReportsEveryModuleInAFolderWithNoMostCommonSuffix(t *testing.T) {
+	modules := []Module{
+		NewModule("a", "/framework/a/", "0.30.0-alpha"),
+		NewModule("b", "/framework/b/", "0.30.0-SNAPSHOT"),
+	}
+	violations := checkModules(modules, CheckPolicy{IsSameSuffixRequired: true})
+
+	assert.Len(t, violations, 2)
+	for _, violation := range violations {
+		assert.Contains(t, violation.Message, "the modules in /framework don't agree on a suffix, they use '-SNAPSHOT', '-alpha'")
+	}
+}
+
+func TestCheckReportsSnapshotModules(t *testing.T) {
+	modules := []Module{
+		NewModule("a", "/a/", "0.30.0"),
+		NewModule("b", "/b/", "0.30.0-SNAPSHOT"),
+	}
+	violations := checkModules(modules, CheckPolicy{IsSnapshotForbidden: true})
+
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "b", violations[0].Module.GetProjectName())
+}
+
+func TestCheckReportsModulesLowerThanReleased(t *testing.T) {
+	modules := []Module{
+		NewModule("a", "/a/", "0.30.0"),
+		NewModule("b", "/b/", "0.31.0"),
+		NewModule("c", "/c/", "0.1.0"),
+	}
+	policy := CheckPolicy{MinimumVersions: map[string]string{"a": "0.30.1", "b": "0.31.0"}}
+	violations := checkModules(modules, policy)
+
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "a", violations[0].Module.GetProjectName())
+	assert.Contains(t, violations[0].Message, "0.30.1")
+}
+
+func TestCheckMatchesSubprojectsToReleasedVersionsByArtifactName(t *testing.T) {
+	modules := []Module{
+		NewModule("galasa-managers-parent:dev.galasa.foo.manager", "/foo/", "0.30.0"),
+	}
+	policy := CheckPolicy{MinimumVersions: map[string]string{"dev.galasa.foo.manager": "0.31.0"}}
+	violations := checkModules(modules, policy)
+
+	assert.Len(t, violations, 1)
+	assert.Contains(t, violations[0].Message, "0.31.0")
+}
+
+func TestCheckFailsWhenSourceTreeBreaksPolicy(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	err := CheckExecute(mockFs, "/my", CheckPolicy{IsSnapshotForbidden: true})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "1 module version policy violations")
+}
+
+func TestCheckPassesWhenSourceTreeFollowsPolicy(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	SuffixSetExecute(mockFs, "/my", "-alpha", "")
+
+	err := CheckExecute(mockFs, "/my", CheckPolicy{IsSameSuffixRequired: true, IsSnapshotForbidden: true})
+	assert.Nil(t, err)
+}
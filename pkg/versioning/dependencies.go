@@ -0,0 +1,154 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"log"
+	"regexp"
+	"strings"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+// The gradle configurations whose dependency declarations we look at.
+const dependencyConfigurations = `(?:implementation|api|compileOnly|runtimeOnly|testImplementation)`
+
+// A version, or a version range which may have spaces in it such as `[0.29.0, 0.30.0]`.
+const dependencyVersion = `[\[(][^"'\])]*[\])]|[^:@"'\s]+`
+
+// To match dependencies like `implementation 'dev.galasa:dev.galasa.foo:0.30.0'`
+// or `api("dev.galasa:dev.galasa.foo:[0.30.0,)")`
+// Sub-match 1 is the group, sub-match 2 is the artifact name, sub-match 3 is the version.
+var dependencyStringRegex = regexp.MustCompile(
+	`(?m)^[ \t]*` + dependencyConfigurations + `[ \t]*\(?[ \t]*["']([^:"'\s]+):([^:"'\s]+):(` + dependencyVersion + `)`)
+
+// To match dependencies like `compileOnly group: 'dev.galasa', name: 'dev.galasa.foo', version: '0.30.0'`
+// Sub-match 1 is the group, sub-match 2 is the artifact name, sub-match 3 is the version.
+var dependencyMapRegex = regexp.MustCompile(
+	`(?m)^[ \t]*` + dependencyConfigurations + `[ \t]*\(?[ \t]*group[ \t]*:[ \t]*["']([^"']*)["'][ \t]*,` +
+		`[ \t]*name[ \t]*:[ \t]*["']([^"']+)["'][ \t]*,[ \t]*version[ \t]*:[ \t]*["']([^"']+)["']`)
+
+// A change to the version of a module, which dependencies on the module need to follow.
+type dependencyVersionChange struct {
+	// The group of the module, or "" if it isn't known, in which case any group matches.
+	GroupId    string
+	OldVersion string
+	NewVersion string
+}
+
+// setSuffixOnDependencyReferences finds dependencies in all the gradle build files in the source
+// folder which refer to one of the modules, and changes them to refer to the module's version
+// with the desired suffix.
+func setSuffixOnDependencyReferences(fs utils.FileSystem, sourceCodeFolderPath string, modules []Module, desiredSuffix string) error {
+	versionChanges := make(map[string]dependencyVersionChange)
+	for _, module := range modules {
+		addDependencyVersionChange(versionChanges, module, calculateDesiredVersion(module.GetVersion(), desiredSuffix))
+	}
+	return substituteDependencyReferences(fs, sourceCodeFolderPath, versionChanges)
+}
+
+// addDependencyVersionChange records the version a module is changing to, under each name a dependency can refer to it by.
+func addDependencyVersionChange(versionChanges map[string]dependencyVersionChange, module Module, newVersion string) {
+	change := dependencyVersionChange{GroupId: module.GetGroupId(), OldVersion: module.GetVersion(), NewVersion: newVersion}
+
+	// Subprojects are referred to by their unqualified name when they are dependencies.
+	versionChanges[module.GetProjectName()] = change
+	versionChanges[module.GetArtifactName()] = change
+}
+
+// substituteDependencyReferences re-writes the dependencies in all the gradle build files in the source
+// folder which refer to a module in versionChanges, so that they refer to the new version of the module.
+func substituteDependencyReferences(fs utils.FileSystem, sourceCodeFolderPath string, versionChanges map[string]dependencyVersionChange) error {
+	buildGradleFilePaths, err := gatherEligibleBuildGradleFiles(fs, sourceCodeFolderPath)
+	for _, buildGradleFilePath := range buildGradleFilePaths {
+		if err != nil {
+			break
+		}
+
+		var contents string
+		contents, err = fs.ReadTextFile(buildGradleFilePath)
+		if err == nil {
+			newContents := substituteDependencyVersions(contents, versionChanges)
+			if newContents != contents {
+				log.Printf("Updating dependency versions in %s\n", buildGradleFilePath)
+				err = fs.WriteTextFile(buildGradleFilePath, newContents)
+			}
+		}
+	}
+
+	return err
+}
+
+// substituteDependencyVersions re-writes the versions of any dependencies in the gradle build file
+// contents which refer to one of the modules in versionChanges, so they refer to the module's new version.
+func substituteDependencyVersions(contents string, versionChanges map[string]dependencyVersionChange) string {
+	for _, regex := range []*regexp.Regexp{dependencyStringRegex, dependencyMapRegex} {
+		allMatches := regex.FindAllStringSubmatchIndex(contents, -1)
+
+		// Work backwards through the matches, so the earlier indexes stay valid as we change things.
+		for index := len(allMatches) - 1; index >= 0; index-- {
+			matches := allMatches[index]
+
+			// matches[2] and matches[3] are the start and end of the group.
+			// matches[4] and matches[5] are the start and end of the artifact name.
+			// matches[6] and matches[7] are the start and end of the version.
+			groupId := contents[matches[2]:matches[3]]
+			artifactName := contents[matches[4]:matches[5]]
+			change, isModule := versionChanges[artifactName]
+			if isModule && (change.GroupId == "" || change.GroupId == groupId) {
+				referencedVersion := contents[matches[6]:matches[7]]
+				newReferencedVersion := calculateNewDependencyVersion(referencedVersion, change)
+				if newReferencedVersion != referencedVersion {
+					contents = contents[:matches[6]] + newReferencedVersion + contents[matches[7]:]
+				} else if !isFixedVersion(referencedVersion) {
+					log.Printf("Leaving the version of dependency %s:%s:%s alone, as it doesn't name the module version\n",
+						groupId, artifactName, referencedVersion)
+				}
+			}
+		}
+	}
+	return contents
+}
+
+// calculateNewDependencyVersion works out what a dependency on a changed module should refer to.
+// A fixed version is changed to the new version of the module, even if it referred to an older one.
+// In a version range such as `[0.30.0,)`, the bounds which name the old version of the module are
+// changed, so that the range still takes in the new version. Dynamic versions such as `0.30.+` and
+// variables can't be changed sensibly, so are left alone.
+func calculateNewDependencyVersion(referencedVersion string, change dependencyVersionChange) string {
+	newVersion := referencedVersion
+
+	if isVersionRange(referencedVersion) {
+		bounds := strings.Split(referencedVersion[1:len(referencedVersion)-1], ",")
+		for index, bound := range bounds {
+			if isOldModuleVersion(strings.TrimSpace(bound), change.OldVersion) {
+				bounds[index] = strings.Replace(bound, strings.TrimSpace(bound), change.NewVersion, 1)
+			}
+		}
+		newVersion = referencedVersion[:1] + strings.Join(bounds, ",") + referencedVersion[len(referencedVersion)-1:]
+	} else if isFixedVersion(referencedVersion) {
+		newVersion = change.NewVersion
+	}
+
+	return newVersion
+}
+
+// isOldModuleVersion returns true if a version range bound names the old version of a module, with
+// or without its suffix. So `0.30.0` names a module which was at `0.30.0-SNAPSHOT`.
+func isOldModuleVersion(bound string, oldVersion string) bool {
+	return bound != "" && (bound == oldVersion || bound == calculateDesiredVersion(oldVersion, ""))
+}
+
+func isFixedVersion(version string) bool {
+	return !isVersionRange(version) && !strings.ContainsAny(version, "+$")
+}
+
+func isVersionRange(version string) bool {
+	return len(version) >= 2 &&
+		strings.ContainsAny(version[:1], "[(]") &&
+		strings.ContainsAny(version[len(version)-1:], "[)]")
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createFooVersionChange(groupId string) map[string]dependencyVersionChange {
+	return map[string]dependencyVersionChange{
+		"dev.galasa.foo": {GroupId: groupId, OldVersion: "0.30.0", NewVersion: "0.30.0-alpha"},
+	}
+}
+
+func TestCanSubstituteDependencyStringNotation(t *testing.T) {
+	contents := `dependencies {
+    implementation 'dev.galasa:dev.galasa.foo:0.30.0'
+    api("dev.galasa:dev.galasa.foo:0.30.0-SNAPSHOT")
+    compileOnly 'dev.galasa:dev.galasa.other:0.30.0'
+}`
+	newContents := substituteDependencyVersions(contents, createFooVersionChange(""))
+
+	assert.Equal(t, `dependencies {
+    implementation 'dev.galasa:dev.galasa.foo:0.30.0-alpha'
+    api("dev.galasa:dev.galasa.foo:0.30.0-alpha")
+    compileOnly 'dev.galasa:dev.galasa.other:0.30.0'
+}`, newContents)
+}
+
+func TestCanSubstituteDependencyMapNotation(t *testing.T) {
+	contents := `    compileOnly group: 'dev.galasa', name: 'dev.galasa.foo', version: '0.30.0'`
+	newContents := substituteDependencyVersions(contents, createFooVersionChange(""))
+
+	assert.Equal(t, `    compileOnly group: 'dev.galasa', name: 'dev.galasa.foo', version: '0.30.0-alpha'`, newContents)
+}
+
+func TestDependencyVersionRangeBoundsNamingTheModuleVersionAreChanged(t *testing.T) {
+	contents := `    implementation 'dev.galasa:dev.galasa.foo:[0.30.0,)'
+    implementation 'dev.galasa:dev.galasa.foo:[0.29.0, 0.30.0]'
+    implementation 'dev.galasa:dev.galasa.foo:[0.28.0,0.29.0)'`
+	newContents := substituteDependencyVersions(contents, createFooVersionChange(""))
+
+	assert.Equal(t, `    implementation 'dev.galasa:dev.galasa.foo:[0.30.0-alpha,)'
+    implementation 'dev.galasa:dev.galasa.foo:[0.29.0, 0.30.0-alpha]'
+    implementation 'dev.galasa:dev.galasa.foo:[0.28.0,0.29.0)'`, newContents)
+}
+
+func TestDependencyVersionRangeBoundsWithoutTheSuffixAreChanged(t *testing.T) {
+	contents := `    implementation 'dev.galasa:dev.galasa.foo:[0.30.0,)'`
+	versionChanges := map[string]dependencyVersionChange{
+		"dev.galasa.foo": {OldVersion: "0.30.0-SNAPSHOT", NewVersion: "0.30.0-alpha"},
+	}
+	newContents := substituteDependencyVersions(contents, versionChanges)
+
+	assert.Equal(t, `    implementation 'dev.galasa:dev.galasa.foo:[0.30.0-alpha,)'`, newContents)
+}
+
+func TestDependenciesInAnotherGroupAreNotChanged(t *testing.T) {
+	contents := `    implementation 'org.other:dev.galasa.foo:0.30.0'
+    compileOnly group: 'org.other', name: 'dev.galasa.foo', version: '0.30.0'
+    implementation 'dev.galasa:dev.galasa.foo:0.30.0'`
+	newContents := substituteDependencyVersions(contents, createFooVersionChange("dev.galasa"))
+
+	assert.Equal(t, `    implementation 'org.other:dev.galasa.foo:0.30.0'
+    compileOnly group: 'org.other', name: 'dev.galasa.foo', version: '0.30.0'
+    implementation 'dev.galasa:dev.galasa.foo:0.30.0-alpha'`, newContents)
+}
+
+func TestDynamicDependencyVersionsAreNotChanged(t *testing.T) {
+	contents := `    implementation 'dev.galasa:dev.galasa.foo:0.30.+'
+    implementation "dev.galasa:dev.galasa.foo:$fooVersion"`
+	newContents := substituteDependencyVersions(contents, createFooVersionChange(""))
+
+	assert.Equal(t, contents, newContents)
+}
+
+func TestSuffixSetUpdatesReferencesToOtherModules(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	mockFs.WriteTextFile("/my/random/folder/module3/build.gradle",
+		`version = "0.36.0-dev"
+dependencies {
+    implementation 'dev.galasa:my.random.folder.module1:0.36.0-SNAPSHOT'
+    implementation 'org.other:not.a.module:1.0.0'
+}`)
+	err := SuffixSetExecute(mockFs, "/my", "-alpha", "")
+	assert.Nil(t, err)
+
+	contents, _ := mockFs.ReadTextFile("/my/random/folder/module3/build.gradle")
+	assert.Equal(t, `version = "0.36.0-alpha"
+dependencies {
+    implementation 'dev.galasa:my.random.folder.module1:0.36.0-alpha'
+    implementation 'org.other:not.a.module:1.0.0'
+}`, contents)
+}
+
+func TestSuffixSetUpdatesStaleReferencesToTheModuleVersion(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	mockFs.WriteTextFile("/my/random/folder/module3/build.gradle",
+		`version = "0.36.0-dev"
+dependencies {
+    implementation 'dev.galasa:my.random.folder.module1:0.35.0'
+}`)
+	err := SuffixSetExecute(mockFs, "/my", "-alpha", "")
+	assert.Nil(t, err)
+
+	// The reference is to the version module1 now has, not to 0.35.0-alpha which was never built.
+	contents, _ := mockFs.ReadTextFile("/my/random/folder/module3/build.gradle")
+	assert.Contains(t, contents, "'dev.galasa:my.random.folder.module1:0.36.0-alpha'")
+}
+
+func TestSuffixSetOnlyUpdatesReferencesInTheModuleGroup(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	mockFs.WriteTextFile("/my/random/folder/module1/build.gradle", `group = 'dev.galasa'
+version = "0.36.0-SNAPSHOT"`)
+	mockFs.WriteTextFile("/my/random/folder/module3/build.gradle",
+		`version = "0.36.0-dev"
+dependencies {
+    implementation 'dev.galasa:my.random.folder.module1:0.36.0-SNAPSHOT'
+    implementation 'org.other:my.random.folder.module1:0.36.0-SNAPSHOT'
+}`)
+	err := SuffixSetExecute(mockFs, "/my", "-alpha", "")
+	assert.Nil(t, err)
+
+	contents, _ := mockFs.ReadTextFile("/my/random/folder/module3/build.gradle")
+	assert.Equal(t, `version = "0.36.0-alpha"
+dependencies {
+    implementation 'dev.galasa:my.random.folder.module1:0.36.0-alpha'
+    implementation 'org.other:my.random.folder.module1:0.36.0-SNAPSHOT'
+}`, contents)
+}
+
+func TestSuffixRemoveUpdatesReferencesInNonModuleBuildFiles(t *testing.T) {
+	mockFs := createTwoModuleFs()
+
+	// A build.gradle with no version, so not a module, which still refers to one.
+	mockFs.MkdirAll("/my/aggregate")
+	mockFs.WriteTextFile("/my/aggregate/build.gradle",
+		`    api 'dev.galasa:my.random.folder.module3:0.36.0-dev'`)
+
+	err := SuffixRemoveExecute(mockFs, "/my", "")
+	assert.Nil(t, err)
+
+	contents, _ := mockFs.ReadTextFile("/my/aggregate/build.gradle")
+	assert.Equal(t, `    api 'dev.galasa:my.random.folder.module3:0.36.0'`, contents)
+}
+
+func TestSubstituteDependencyReferencesLeavesUnchangedFilesAlone(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	writeCount := 0
+	mockFs.VirtualFunction_WriteTextFile = func(targetFilePath string, desiredContents string) error {
+		writeCount++
+		return nil
+	}
+
+	modules, _ := getModules(mockFs, "/my")
+	err := setSuffixOnDependencyReferences(mockFs, "/my", modules, "-alpha")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, writeCount)
+}
+
+func TestCanSubstituteDependenciesWithNoModules(t *testing.T) {
+	mockFs := utils.NewOverridableMockFileSystem()
+	mockFs.MkdirAll("/my")
+	err := setSuffixOnDependencyReferences(mockFs, "/my", []Module{}, "-alpha")
+	assert.Nil(t, err)
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+// The differences between two sets of module versions.
+type VersionDiff struct {
+	Added      []ModuleVersion       `json:"added"`
+	Removed    []ModuleVersion       `json:"removed"`
+	Bumped     []ModuleVersionChange `json:"bumped"`
+	Downgraded []ModuleVersionChange `json:"downgraded"`
+	Unchanged  int                   `json:"unchanged"`
+}
+
+type ModuleVersion struct {
+	ProjectName string `json:"projectName"`
+	Version     string `json:"version"`
+}
+
+type ModuleVersionChange struct {
+	ProjectName string `json:"projectName"`
+	FromVersion string `json:"fromVersion"`
+	ToVersion   string `json:"toVersion"`
+}
+
+// GetModuleVersions finds all the modules in the source code folder, returning their versions
+// keyed by artifact name, so that they can be compared with the bundles of release metadata.
+func GetModuleVersions(fs utils.FileSystem, sourceCodeFolderPath string) (map[string]string, error) {
+	moduleVersions := make(map[string]string)
+
+	modules, err := getModules(fs, sourceCodeFolderPath)
+	if err == nil {
+		for _, module := range modules {
+			moduleVersions[module.GetArtifactName()] = module.GetVersion()
+		}
+	}
+
+	return moduleVersions, err
+}
+
+// DiffExecute compares the versions of the modules in the source code folder against an earlier
+// set of versions, printing the modules which were added, removed, bumped or downgraded.
+func DiffExecute(fs utils.FileSystem, fromVersions map[string]string, toSourceCodeFolderPath string, format string) error {
+	var err error
+
+	if format != LIST_FORMAT_TEXT && format != LIST_FORMAT_JSON {
+		err = fmt.Errorf("Invalid format '%s'. It must be one of '%s' or '%s'.", format, LIST_FORMAT_TEXT, LIST_FORMAT_JSON)
+	}
+
+	if err == nil {
+		var toVersions map[string]string
+		toVersions, err = GetModuleVersions(fs, toSourceCodeFolderPath)
+		if err == nil {
+			diff := diffModuleVersions(fromVersions, toVersions)
+			err = printVersionDiff(os.Stdout, diff, format)
+		}
+	}
+
+	return err
+}
+
+func diffModuleVersions(fromVersions map[string]string, toVersions map[string]string) VersionDiff {
+	diff := VersionDiff{
+		Added:      make([]ModuleVersion, 0),
+		Removed:    make([]ModuleVersion, 0),
+		Bumped:     make([]ModuleVersionChange, 0),
+		Downgraded: make([]ModuleVersionChange, 0),
+	}
+
+	for _, projectName := range sortedKeys(toVersions) {
+		toVersion := toVersions[projectName]
+		fromVersion, isFound := fromVersions[projectName]
+
+		if !isFound {
+			diff.Added = append(diff.Added, ModuleVersion{ProjectName: projectName, Version: toVersion})
+		} else {
+			change := ModuleVersionChange{ProjectName: projectName, FromVersion: fromVersion, ToVersion: toVersion}
+			comparison := utils.CompareVersions(toVersion, fromVersion)
+			if comparison > 0 {
+				diff.Bumped = append(diff.Bumped, change)
+			} else if comparison < 0 {
+				diff.Downgraded = append(diff.Downgraded, change)
+			} else {
+				diff.Unchanged++
+			}
+		}
+	}
+
+	for _, projectName := range sortedKeys(fromVersions) {
+		if _, isFound := toVersions[projectName]; !isFound {
+			diff.Removed = append(diff.Removed, ModuleVersion{ProjectName: projectName, Version: fromVersions[projectName]})
+		}
+	}
+
+	return diff
+}
+
+func sortedKeys(versions map[string]string) []string {
+	keys := make([]string, 0, len(versions))
+	for key := range versions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func printVersionDiff(writer io.Writer, diff VersionDiff, format string) error {
+	var err error
+
+	if format == LIST_FORMAT_JSON {
+		var bytes []byte
+		bytes, err = json.MarshalIndent(diff, "", "  ")
+		if err == nil {
+			fmt.Fprintln(writer, string(bytes))
+		}
+	} else {
+		for _, module := range diff.Added {
+			fmt.Fprintf(writer, "added %s %s\n", module.ProjectName, module.Version)
+		}
+		for _, module := range diff.Removed {
+			fmt.Fprintf(writer, "removed %s %s\n", module.ProjectName, module.Version)
+		}
+		for _, change := range diff.Bumped {
+			fmt.Fprintf(writer, "bumped %s %s -> %s\n", change.ProjectName, change.FromVersion, change.ToVersion)
+		}
+		for _, change := range diff.Downgraded {
+			fmt.Fprintf(writer, "downgraded %s %s -> %s\n", change.ProjectName, change.FromVersion, change.ToVersion)
+		}
+		fmt.Fprintf(writer, "%d added, %d removed, %d bumped, %d downgraded, %d unchanged\n",
+			len(diff.Added), len(diff.Removed), len(diff.Bumped), len(diff.Downgraded), diff.Unchanged)
+	}
+
+	return err
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanDiffModuleVersions(t *testing.T) {
+	fromVersions := map[string]string{
+		"same":       "0.30.0",
+		"bumped":     "0.30.0",
+		"downgraded": "0.30.0",
+		"removed":    "0.1.0",
+	}
+	toVersions := map[string]string{
+		"same":       "0.30.0",
+		"bumped":     "0.31.0",
+		"downgraded": "0.30.0-SNAPSHOT",
+		"added":      "0.2.0",
+	}
+
+	diff := diffModuleVersions(fromVersions, toVersions)
+
+	assert.Equal(t, []ModuleVersion{{ProjectName: "added", Version: "0.2.0"}}, diff.Added)
+	assert.Equal(t, []ModuleVersion{{ProjectName: "removed", Version: "0.1.0"}}, diff.Removed)
+	assert.Equal(t, []ModuleVersionChange{{ProjectName: "bumped", FromVersion: "0.30.0", ToVersion: "0.31.0"}}, diff.Bumped)
+	assert.Equal(t, []ModuleVersionChange{{ProjectName: "downgraded", FromVersion: "0.30.0", ToVersion: "0.30.0-SNAPSHOT"}}, diff.Downgraded)
+	assert.Equal(t, 1, diff.Unchanged)
+}
+
+func TestCanPrintVersionDiffAsText(t *testing.T) {
+	diff := diffModuleVersions(map[string]string{"a": "1.0.0", "b": "1.0.0"}, map[string]string{"a": "1.1.0", "c": "1.0.0"})
+
+	var buffer bytes.Buffer
+	err := printVersionDiff(&buffer, diff, LIST_FORMAT_TEXT)
+	assert.Nil(t, err)
+	assert.Equal(t, "added c 1.0.0\nremoved b 1.0.0\nbumped a 1.0.0 -> 1.1.0\n"+
+		"1 added, 1 removed, 1 bumped, 0 downgraded, 0 unchanged\n", buffer.String())
+}
+
+func TestCanPrintVersionDiffAsJson(t *testing.T) {
+	diff := diffModuleVersions(map[string]string{"a": "1.0.0"}, map[string]string{"a": "0.9.0"})
+
+	var buffer bytes.Buffer
+	err := printVersionDiff(&buffer, diff, LIST_FORMAT_JSON)
+	assert.Nil(t, err)
+
+	var diffGotBack VersionDiff
+	err = json.Unmarshal(buffer.Bytes(), &diffGotBack)
+	assert.Nil(t, err)
+	assert.Equal(t, diff, diffGotBack)
+}
+
+func TestDiffFailsIfFormatIsInvalid(t *testing.T) {
+	err := DiffExecute(nil, map[string]string{}, "/my", LIST_FORMAT_CSV)
+	assert.NotNil(t, err)
+}
+
+func TestCanDiffAgainstSourceTree(t *testing.T) {
+	mockFs := createTwoModuleFs()
+
+	fromVersions, err := GetModuleVersions(mockFs, "/my")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"my.random.folder.module1": "0.36.0-SNAPSHOT",
+		"my.random.folder.module3": "0.36.0-dev",
+	}, fromVersions)
+
+	err = DiffExecute(mockFs, fromVersions, "/my", LIST_FORMAT_TEXT)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+// To match `include 'a', 'b'`, `include(":a:b")` and lists which carry on over several lines.
+// Sub-match 1 is the list of quoted subproject paths.
+var includeRegex = regexp.MustCompile(`(?m)^[ \t]*include[ \t]*\(?[ \t]*((?:["'][^"'\n]+["'][ \t]*,?\s*)+)`)
+var quotedStringRegex = regexp.MustCompile(`["']([^"'\n]+)["']`)
+
+// gatherGradleSubprojects finds the subprojects included by all the settings.gradle files under the
+// source code folder. The result maps each subproject folder to its qualified name, which is the
+// root project name followed by the gradle project path, for example `my.root:my.subproject`.
+//
+// Folders which have a settings.gradle file of their own are modules in their own right, so
+// are not treated as subprojects.
+func gatherGradleSubprojects(fs utils.FileSystem, sourceCodeFolderPath string) (map[string]string, error) {
+	subprojectNames := make(map[string]string)
+
+	filePaths, err := fs.GetAllFilePaths(sourceCodeFolderPath)
+	if err != nil {
+		log.Printf("impossible to walk directories: %s", err)
+	} else {
+		sort.Strings(filePaths)
+
+		settingsFolders := make(map[string]bool)
+		var settingsFilePaths []string
+		for _, filePath := range filePaths {
+			dirPart, filePart := path.Split(filePath)
+			if filePart == SETTINGS_GRADLE_FILE_NAME || filePart == SETTINGS_GRADLE_KTS_FILE_NAME {
+				settingsFolders[dirPart] = true
+				settingsFilePaths = append(settingsFilePaths, filePath)
+			}
+		}
+
+		for _, settingsFilePath := range settingsFilePaths {
+			var contents string
+			contents, err = fs.ReadTextFile(settingsFilePath)
+			if err != nil {
+				break
+			}
+
+			settingsFolderPath, _ := path.Split(settingsFilePath)
+			for subprojectFolderPath, qualifiedName := range extractSubprojectsFromGradleSettings(contents, settingsFolderPath) {
+				if !settingsFolders[subprojectFolderPath] {
+					subprojectNames[subprojectFolderPath] = qualifiedName
+				}
+			}
+		}
+	}
+
+	return subprojectNames, err
+}
+
+// extractSubprojectsFromGradleSettings finds the subprojects included in the contents of a
+// settings.gradle file, returning a map of subproject folder to qualified name.
+func extractSubprojectsFromGradleSettings(contents string, settingsFolderPath string) map[string]string {
+	subprojects := make(map[string]string)
+
+	// Gradle uses the folder name if the root project isn't given a name.
+	rootProjectName := path.Base(settingsFolderPath)
+	matches := projectNameRegex.FindStringSubmatch(contents)
+	if matches != nil {
+		rootProjectName = matches[1]
+	}
+
+	for _, includeMatches := range includeRegex.FindAllStringSubmatch(contents, -1) {
+		for _, quotedMatches := range quotedStringRegex.FindAllStringSubmatch(includeMatches[1], -1) {
+			projectPath := strings.TrimPrefix(strings.TrimSpace(quotedMatches[1]), ":")
+			if projectPath != "" {
+				// A project path of a:b lives in the a/b folder.
+				pathParts := strings.Split(projectPath, ":")
+				subprojectFolderPath := path.Join(settingsFolderPath, path.Join(pathParts...)) + "/"
+				subprojects[subprojectFolderPath] = rootProjectName + ":" + projectPath
+			}
+		}
+	}
+
+	return subprojects
+}
+
+func extractModulesFromGradleSubprojects(fs utils.FileSystem, subprojectFolderPaths []string, subprojectNames map[string]string) ([]Module, error) {
+	var err error
+	var modules []Module = make([]Module, 0)
+
+	for _, subprojectFolderPath := range subprojectFolderPaths {
+		var version, versionFilePath string
+		version, versionFilePath, err = extractVersionFromBuildGradleFolder(fs, subprojectFolderPath)
+		if err != nil {
+			log.Printf("Error extracting the module from gradle subproject folder. %v", err)
+			break
+		}
+
+		if version == "" {
+			log.Printf("Warning: build.gradle file has no version line so subproject folder %s does not contain a module.\n", subprojectFolderPath)
+		} else {
+			var groupId string
+			groupId, err = extractGroupFromBuildGradleFolder(fs, subprojectFolderPath)
+			if err != nil {
+				break
+			}
+			modules = append(modules, NewModuleWithGroupId(groupId, subprojectNames[subprojectFolderPath], subprojectFolderPath, version, versionFilePath))
+		}
+	}
+
+	return modules, err
+}
+
+// unqualifiedProjectName returns the last part of a qualified subproject name, which is the
+// name gradle publishes the subproject with by default. Names which aren't qualified are
+// returned as they are.
+func unqualifiedProjectName(projectName string) string {
+	return projectName[strings.LastIndex(projectName, ":")+1:]
+}
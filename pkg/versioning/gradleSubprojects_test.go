@@ -0,0 +1,110 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMultiProjectFs() *utils.MockFileSystem {
+	fs := utils.NewOverridableMockFileSystem()
+
+	// The root of a multi-project build, with no version of its own.
+	fs.MkdirAll("/my/managers")
+	fs.WriteTextFile("/my/managers/settings.gradle",
+		`rootProject.name = 'galasa-managers-parent'
+
+include 'dev.galasa.foo.manager', 'dev.galasa.bar.manager'
+include(':zos:dev.galasa.zos.manager')
+`)
+	fs.WriteTextFile("/my/managers/build.gradle", `// No version here`)
+
+	fs.MkdirAll("/my/managers/dev.galasa.foo.manager")
+	fs.WriteTextFile("/my/managers/dev.galasa.foo.manager/build.gradle",
+		`version = '0.30.0'
+dependencies {
+    implementation 'dev.galasa:dev.galasa.bar.manager:0.31.0'
+}`)
+
+	fs.MkdirAll("/my/managers/dev.galasa.bar.manager")
+	fs.WriteTextFile("/my/managers/dev.galasa.bar.manager/build.gradle", `version = '0.31.0'`)
+
+	fs.MkdirAll("/my/managers/zos/dev.galasa.zos.manager")
+	fs.WriteTextFile("/my/managers/zos/dev.galasa.zos.manager/build.gradle", `version = '0.32.0-SNAPSHOT'`)
+	return fs
+}
+
+func TestCanExtractSubprojectsFromGradleSettings(t *testing.T) {
+	subprojects := extractSubprojectsFromGradleSettings(`rootProject.name = "root"
+include("a",
+        "b")
+include ':c:d'
+`, "/src/")
+
+	assert.Equal(t, map[string]string{
+		"/src/a/":   "root:a",
+		"/src/b/":   "root:b",
+		"/src/c/d/": "root:c:d",
+	}, subprojects)
+}
+
+func TestSubprojectsUseFolderNameIfRootProjectHasNoName(t *testing.T) {
+	subprojects := extractSubprojectsFromGradleSettings(`include 'a'`, "/src/myroot/")
+
+	assert.Equal(t, map[string]string{"/src/myroot/a/": "myroot:a"}, subprojects)
+}
+
+func TestCanFindModulesInSubprojects(t *testing.T) {
+	fs := createMultiProjectFs()
+
+	modules, err := getModules(fs, "/my")
+	assert.Nil(t, err)
+	assert.Len(t, modules, 3)
+
+	assert.Equal(t, "galasa-managers-parent:dev.galasa.bar.manager", modules[0].GetProjectName())
+	assert.Equal(t, "dev.galasa.bar.manager", modules[0].GetArtifactName())
+	assert.Equal(t, "/my/managers/dev.galasa.bar.manager/", modules[0].GetPath())
+	assert.Equal(t, "0.31.0", modules[0].GetVersion())
+
+	assert.Equal(t, "galasa-managers-parent:dev.galasa.foo.manager", modules[1].GetProjectName())
+	assert.Equal(t, "0.30.0", modules[1].GetVersion())
+
+	assert.Equal(t, "galasa-managers-parent:zos:dev.galasa.zos.manager", modules[2].GetProjectName())
+	assert.Equal(t, "dev.galasa.zos.manager", modules[2].GetArtifactName())
+	assert.Equal(t, "/my/managers/zos/dev.galasa.zos.manager/build.gradle", modules[2].GetVersionFilePath())
+}
+
+func TestSubprojectWithItsOwnSettingsIsNotQualified(t *testing.T) {
+	fs := createMultiProjectFs()
+	fs.WriteTextFile("/my/managers/dev.galasa.bar.manager/settings.gradle", `rootProject.name = 'dev.galasa.bar.manager'`)
+
+	modules, err := getModules(fs, "/my")
+	assert.Nil(t, err)
+	assert.Len(t, modules, 3)
+	assert.Equal(t, "dev.galasa.bar.manager", modules[0].GetProjectName())
+}
+
+func TestSuffixSetAppliesToSubprojects(t *testing.T) {
+	fs := createMultiProjectFs()
+
+	err := SuffixSetExecute(fs, "/my", "-alpha", "")
+	assert.Nil(t, err)
+
+	modules, err := getModules(fs, "/my")
+	assert.Nil(t, err)
+	assert.Len(t, modules, 3)
+	for _, module := range modules {
+		assert.Equal(t, "-alpha", module.GetVersion()[len(module.GetVersion())-6:])
+	}
+
+	// References to subprojects are by their unqualified name.
+	contents, _ := fs.ReadTextFile("/my/managers/dev.galasa.foo.manager/build.gradle")
+	assert.Contains(t, contents, "'dev.galasa:dev.galasa.bar.manager:0.31.0-alpha'")
+}
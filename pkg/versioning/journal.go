@@ -0,0 +1,191 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+const DEFAULT_JOURNAL_FILE_NAME = "versioning-journal.json"
+
+// A record of the original contents of files, from before they were changed, so that the
+// changes can be undone.
+type Journal struct {
+	Files []JournalFile `json:"files"`
+}
+
+type JournalFile struct {
+	Path     string `json:"path"`
+	Contents string `json:"contents"`
+}
+
+// stagingFileSystem collects the text files written through it in memory, rather than changing
+// the real files, so a set of edits can be worked out in full before any of them are applied.
+// Files which have been written are read back from memory. Everything else is passed through.
+type stagingFileSystem struct {
+	utils.FileSystem
+	stagedContents map[string]string
+	stagedPaths    []string
+}
+
+func newStagingFileSystem(fs utils.FileSystem) *stagingFileSystem {
+	staging := new(stagingFileSystem)
+	staging.FileSystem = fs
+	staging.stagedContents = make(map[string]string)
+	return staging
+}
+
+func (staging *stagingFileSystem) ReadTextFile(filePath string) (string, error) {
+	var err error
+	contents, isStaged := staging.stagedContents[filePath]
+	if !isStaged {
+		contents, err = staging.FileSystem.ReadTextFile(filePath)
+	}
+	return contents, err
+}
+
+func (staging *stagingFileSystem) WriteTextFile(targetFilePath string, desiredContents string) error {
+	if _, isStaged := staging.stagedContents[targetFilePath]; !isStaged {
+		staging.stagedPaths = append(staging.stagedPaths, targetFilePath)
+	}
+	staging.stagedContents[targetFilePath] = desiredContents
+	return nil
+}
+
+// runTransaction calls the edit function with a staging file system, so none of the files are
+// changed unless all the edits can be worked out. The original contents of the files which are
+// about to change are written to a journal file, then the changes are applied. If applying the
+// changes fails part way through, the files already changed are put back as they were.
+//
+// If the journal file path is blank, the journal is written to a new temporary folder.
+func runTransaction(fs utils.FileSystem, journalFilePath string, edit func(fs utils.FileSystem) error) error {
+	staging := newStagingFileSystem(fs)
+
+	err := edit(staging)
+	if err == nil {
+		var journal Journal
+		journal, err = createJournal(fs, staging)
+
+		if err == nil && len(journal.Files) > 0 {
+			journalFilePath, err = writeJournal(fs, journalFilePath, journal)
+			if err == nil {
+				err = applyStagedEdits(fs, staging, journal, journalFilePath)
+			}
+		}
+	}
+
+	return err
+}
+
+func createJournal(fs utils.FileSystem, staging *stagingFileSystem) (Journal, error) {
+	var err error
+	journal := Journal{Files: make([]JournalFile, 0)}
+
+	for _, stagedPath := range staging.stagedPaths {
+		var originalContents string
+		originalContents, err = fs.ReadTextFile(stagedPath)
+		if err != nil {
+			break
+		}
+
+		// Files which end up the same as they started don't need to be written at all.
+		if originalContents != staging.stagedContents[stagedPath] {
+			journal.Files = append(journal.Files, JournalFile{Path: stagedPath, Contents: originalContents})
+		}
+	}
+
+	return journal, err
+}
+
+func writeJournal(fs utils.FileSystem, journalFilePath string, journal Journal) (string, error) {
+	var err error
+
+	if journalFilePath == "" {
+		var tempFolderPath string
+		tempFolderPath, err = fs.MkTempDir()
+		journalFilePath = path.Join(tempFolderPath, DEFAULT_JOURNAL_FILE_NAME)
+	}
+
+	if err == nil {
+		var bytes []byte
+		bytes, err = json.MarshalIndent(journal, "", "  ")
+		if err == nil {
+			err = fs.WriteTextFile(journalFilePath, string(bytes))
+		}
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed to write the journal file %s, so no files were changed - %s", journalFilePath, err.Error())
+	} else {
+		log.Printf("The original contents of %d files were written to the journal file %s\n", len(journal.Files), journalFilePath)
+	}
+
+	return journalFilePath, err
+}
+
+func applyStagedEdits(fs utils.FileSystem, staging *stagingFileSystem, journal Journal, journalFilePath string) error {
+	var err error
+
+	for index, journalFile := range journal.Files {
+		err = fs.WriteTextFile(journalFile.Path, staging.stagedContents[journalFile.Path])
+		if err != nil {
+			// Put back the files we have changed so far.
+			rollbackErr := restoreJournalFiles(fs, journal.Files[:index])
+			if rollbackErr == nil {
+				err = fmt.Errorf("failed to change file %s, so the changes were rolled back - %s", journalFile.Path, err.Error())
+			} else {
+				err = fmt.Errorf("failed to change file %s, and failed to roll back the changes. "+
+					"Use 'galasabld versioning undo --journal %s' to restore the files - %s",
+					journalFile.Path, journalFilePath, err.Error())
+			}
+			break
+		}
+	}
+
+	return err
+}
+
+func restoreJournalFiles(fs utils.FileSystem, journalFiles []JournalFile) error {
+	var err error
+	for _, journalFile := range journalFiles {
+		err = fs.WriteTextFile(journalFile.Path, journalFile.Contents)
+		if err != nil {
+			err = fmt.Errorf("failed to restore file %s - %s", journalFile.Path, err.Error())
+			break
+		}
+	}
+	return err
+}
+
+// UndoExecute restores the files recorded in a journal file to their original contents.
+func UndoExecute(fs utils.FileSystem, journalFilePath string) error {
+	var journal Journal
+
+	contents, err := fs.ReadTextFile(journalFilePath)
+	if err != nil {
+		err = fmt.Errorf("failed to read the journal file %s - %s", journalFilePath, err.Error())
+	} else {
+		err = json.Unmarshal([]byte(contents), &journal)
+		if err != nil {
+			err = fmt.Errorf("failed to parse the journal file %s - %s", journalFilePath, err.Error())
+		}
+	}
+
+	if err == nil {
+		err = restoreJournalFiles(fs, journal.Files)
+		if err == nil {
+			log.Printf("Restored %d files from the journal file %s\n", len(journal.Files), journalFilePath)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"errors"
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStagingFileSystemDoesNotChangeRealFiles(t *testing.T) {
+	mockFs := utils.NewOverridableMockFileSystem()
+	mockFs.WriteTextFile("/a.txt", "original")
+
+	staging := newStagingFileSystem(mockFs)
+	staging.WriteTextFile("/a.txt", "changed")
+
+	stagedContents, _ := staging.ReadTextFile("/a.txt")
+	assert.Equal(t, "changed", stagedContents)
+
+	realContents, _ := mockFs.ReadTextFile("/a.txt")
+	assert.Equal(t, "original", realContents)
+}
+
+func TestSuffixSetWritesJournalOfOriginalContents(t *testing.T) {
+	mockFs := createTwoModuleFs()
+
+	err := SuffixSetExecute(mockFs, "/my", "-alpha", "/journal.json")
+	assert.Nil(t, err)
+
+	journalContents, err := mockFs.ReadTextFile("/journal.json")
+	assert.Nil(t, err)
+	assert.Contains(t, journalContents, "/my/random/folder/module1/build.gradle")
+	assert.Contains(t, journalContents, "0.36.0-SNAPSHOT")
+	assert.Contains(t, journalContents, "0.36.0-dev")
+}
+
+func TestCanUndoSuffixSetFromJournal(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	originalContents, _ := mockFs.ReadTextFile("/my/random/folder/module1/build.gradle")
+
+	err := SuffixSetExecute(mockFs, "/my", "-alpha", "/journal.json")
+	assert.Nil(t, err)
+
+	err = UndoExecute(mockFs, "/journal.json")
+	assert.Nil(t, err)
+
+	contents, _ := mockFs.ReadTextFile("/my/random/folder/module1/build.gradle")
+	assert.Equal(t, originalContents, contents)
+
+	modules, _ := getModules(mockFs, "/my")
+	assert.Equal(t, "0.36.0-SNAPSHOT", modules[0].GetVersion())
+	assert.Equal(t, "0.36.0-dev", modules[1].GetVersion())
+}
+
+func TestUndoFailsIfJournalIsMissing(t *testing.T) {
+	mockFs := utils.NewOverridableMockFileSystem()
+	err := UndoExecute(mockFs, "/journal.json")
+	assert.NotNil(t, err)
+}
+
+func TestNoFilesChangeIfAnEditCannotBeWorkedOut(t *testing.T) {
+	mockFs := createTwoModuleFs()
+
+	// A module with no build file, so its version can't be re-written.
+	modules, _ := getModules(mockFs, "/my")
+	modules = append(modules, NewModule("broken", "/my/broken/", "1.0.0"))
+
+	err := runTransaction(mockFs, "/journal.json", func(stagingFs utils.FileSystem) error {
+		return setSuffixOnAllModules(stagingFs, modules, "-alpha")
+	})
+	assert.NotNil(t, err)
+
+	contents, _ := mockFs.ReadTextFile("/my/random/folder/module1/build.gradle")
+	assert.Contains(t, contents, "0.36.0-SNAPSHOT")
+	isJournalWritten, _ := mockFs.Exists("/journal.json")
+	assert.False(t, isJournalWritten)
+}
+
+func TestChangesAreRolledBackIfWritingFailsPartWay(t *testing.T) {
+	mockFs := createTwoModuleFs()
+	originalWriteTextFile := mockFs.VirtualFunction_WriteTextFile
+	mockFs.VirtualFunction_WriteTextFile = func(targetFilePath string, desiredContents string) error {
+		var err error
+		if targetFilePath == "/my/random/folder/module3/build.gradle" {
+			err = errors.New("simulated failure")
+		} else {
+			err = originalWriteTextFile(targetFilePath, desiredContents)
+		}
+		return err
+	}
+
+	err := SuffixSetExecute(mockFs, "/my", "-alpha", "/journal.json")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "rolled back")
+
+	contents, _ := mockFs.ReadTextFile("/my/random/folder/module1/build.gradle")
+	assert.Contains(t, contents, "0.36.0-SNAPSHOT")
+}
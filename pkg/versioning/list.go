@@ -14,7 +14,6 @@ import (
 	"path"
 	"regexp"
 	"sort"
-	"strings"
 
 	"galasa.dev/buildUtilities/pkg/utils"
 )
@@ -24,17 +23,42 @@ import (
 var versionLineRegex = regexp.MustCompile(`(?m)^[ \t]*version[ \t]*[=]?[\t ]*["'](.*)['"].*$`)
 var projectNameRegex = regexp.MustCompile(`(?m)^rootProject.name[\t ]*=[\t ]*["'](.*)["'].*$`)
 
-func ListExecute(fs utils.FileSystem, sourceCodeFolderPath string) error {
+// To match the `group = "a.b.c"` pattern
+var groupLineRegex = regexp.MustCompile(`(?m)^[ \t]*group[ \t]*=[\t ]*["']([^"']*)['"].*$`)
 
-	modules, err := getModules(fs, sourceCodeFolderPath)
+// To match the `version=x.y.z` pattern in a gradle.properties file.
+var gradlePropertiesVersionRegex = regexp.MustCompile(`(?m)^[ \t]*version[ \t]*[=:][ \t]*([^\s#!]+)[ \t]*$`)
+
+const (
+	BUILD_GRADLE_FILE_NAME        = "build.gradle"
+	BUILD_GRADLE_KTS_FILE_NAME    = "build.gradle.kts"
+	SETTINGS_GRADLE_FILE_NAME     = "settings.gradle"
+	SETTINGS_GRADLE_KTS_FILE_NAME = "settings.gradle.kts"
+	GRADLE_PROPERTIES_FILE_NAME   = "gradle.properties"
+)
+
+func ListExecute(fs utils.FileSystem, sourceCodeFolderPath string, format string) error {
+
+	err := validateListFormat(format)
 
 	if err == nil {
-		err = printModuleListing(modules)
+		var modules []Module
+		modules, err = getModules(fs, sourceCodeFolderPath)
+
+		if err == nil {
+			err = printModuleListing(modules, format)
+		}
 	}
 
 	return err
 }
 
+// GetModules finds all the gradle and maven modules in the source code folder recursively,
+// sorted by project name.
+func GetModules(fs utils.FileSystem, sourceCodeFolderPath string) ([]Module, error) {
+	return getModules(fs, sourceCodeFolderPath)
+}
+
 func getModules(fs utils.FileSystem, sourceCodeFolderPath string) ([]Module, error) {
 	var modules []Module
 	var err error
@@ -46,9 +70,49 @@ func getModules(fs utils.FileSystem, sourceCodeFolderPath string) ([]Module, err
 		var buildGradleFolderPaths []string
 		buildGradleFolderPaths, err = gatherEligibleBuildGradleFolders(fs, sourceCodeFolderPath)
 
+		// Subprojects included by a settings.gradle file in a parent folder are found separately,
+		// as they have no settings.gradle of their own.
+		var subprojectNames map[string]string
+		if err == nil {
+			subprojectNames, err = gatherGradleSubprojects(fs, sourceCodeFolderPath)
+		}
+
 		if err == nil {
-			modules, err = extractModulesFromBuildGradleFolders(fs, buildGradleFolderPaths)
+			var topLevelFolderPaths []string
+			var subprojectFolderPaths []string
+			for _, buildGradleFolderPath := range buildGradleFolderPaths {
+				if _, isSubproject := subprojectNames[buildGradleFolderPath]; isSubproject {
+					subprojectFolderPaths = append(subprojectFolderPaths, buildGradleFolderPath)
+				} else {
+					topLevelFolderPaths = append(topLevelFolderPaths, buildGradleFolderPath)
+				}
+			}
+
+			modules, err = extractModulesFromBuildGradleFolders(fs, topLevelFolderPaths)
+
+			if err == nil {
+				var subprojectModules []Module
+				subprojectModules, err = extractModulesFromGradleSubprojects(fs, subprojectFolderPaths, subprojectNames)
+				modules = append(modules, subprojectModules...)
+			}
+		}
+
+		if err == nil {
+			// Maven modules live in folders with a pom.xml file, which are not also gradle modules.
+			var pomFilePaths []string
+			pomFilePaths, err = gatherEligiblePomFiles(fs, sourceCodeFolderPath, buildGradleFolderPaths)
+
+			if err == nil {
+				var mavenModules []Module
+				mavenModules, err = extractModulesFromPomFiles(fs, pomFilePaths)
+				modules = append(modules, mavenModules...)
+			}
 		}
+
+		// Sort the results by project name.
+		sort.Slice(modules, func(i, j int) bool {
+			return modules[i].GetProjectName() < modules[j].GetProjectName()
+		})
 	}
 
 	return modules, err
@@ -65,11 +129,12 @@ func checkFolderExists(fs utils.FileSystem, sourceCodeFolderPath string) error {
 	return err
 }
 
-func printModuleListing(modules []Module) error {
-	for _, module := range modules {
-		fmt.Fprintf(os.Stdout, "%s %s\n", module.GetProjectName(), module.GetVersion())
+func printModuleListing(modules []Module, format string) error {
+	output, err := formatModuleListing(modules, format)
+	if err == nil {
+		fmt.Fprint(os.Stdout, output)
 	}
-	return nil
+	return err
 }
 
 func extractModulesFromBuildGradleFolders(fs utils.FileSystem, buildGradleFolderPaths []string) ([]Module, error) {
@@ -101,26 +166,22 @@ func extractModulesFromBuildGradleFolders(fs utils.FileSystem, buildGradleFolder
 func extractModuleFromBuildGradleFolder(fs utils.FileSystem, buildGradleFolderPath string) (Module, error) {
 	var module Module
 
-	buildGradleFilePath := path.Join(buildGradleFolderPath, "build.gradle")
+	version, versionFilePath, err := extractVersionFromBuildGradleFolder(fs, buildGradleFolderPath)
 
-	contentsString, err := fs.ReadTextFile(buildGradleFilePath)
 	if err == nil {
-		matches := versionLineRegex.FindStringSubmatch(contentsString)
-
-		if matches == nil {
-			// There was no version in this build.gradle file. Warning ?
+		if version == "" {
+			// There was no version in the build.gradle file. Warning ?
 			log.Printf("Warning: build.gradle file has no version line so folder %s does not contain a module.\n", buildGradleFolderPath)
 		} else {
-			// There is a match.
-			version := matches[1]
-
 			var projectName string
 			projectName, err = extractProjectNameFromGradleSettings(fs, buildGradleFolderPath)
 
 			if err == nil {
 
 				if projectName != "" {
-					module = NewModule(projectName, buildGradleFolderPath, version)
+					var groupId string
+					groupId, err = extractGroupFromBuildGradleFolder(fs, buildGradleFolderPath)
+					module = NewModuleWithGroupId(groupId, projectName, buildGradleFolderPath, version, versionFilePath)
 				}
 			}
 		}
@@ -129,26 +190,95 @@ func extractModuleFromBuildGradleFolder(fs utils.FileSystem, buildGradleFolderPa
 	return module, err
 }
 
+// extractVersionFromBuildGradleFolder finds the version of the gradle project in a folder, and
+// the path of the file it was found in. A blank version is returned if there isn't one.
+func extractVersionFromBuildGradleFolder(fs utils.FileSystem, buildGradleFolderPath string) (string, string, error) {
+	var version string
+	var versionFilePath string
+
+	// The groovy build.gradle is preferred over the kotlin build.gradle.kts if there are both.
+	buildGradleFilePath, err := findFirstExistingFile(fs, buildGradleFolderPath, BUILD_GRADLE_FILE_NAME, BUILD_GRADLE_KTS_FILE_NAME)
+	if err == nil && buildGradleFilePath != "" {
+		var contentsString string
+		contentsString, err = fs.ReadTextFile(buildGradleFilePath)
+		if err == nil {
+			matches := versionLineRegex.FindStringSubmatch(contentsString)
+			if matches != nil {
+				version = matches[1]
+				versionFilePath = buildGradleFilePath
+			}
+		}
+	}
+
+	if err == nil && version == "" {
+		// The version may be declared in the gradle.properties file instead.
+		version, versionFilePath, err = extractVersionFromGradleProperties(fs, buildGradleFolderPath)
+	}
+
+	return version, versionFilePath, err
+}
+
+// extractGroupFromBuildGradleFolder finds the group the gradle project in a folder is published with.
+// A blank group is returned if the build.gradle file doesn't set one.
+func extractGroupFromBuildGradleFolder(fs utils.FileSystem, buildGradleFolderPath string) (string, error) {
+	var groupId string
+
+	buildGradleFilePath, err := findFirstExistingFile(fs, buildGradleFolderPath, BUILD_GRADLE_FILE_NAME, BUILD_GRADLE_KTS_FILE_NAME)
+	if err == nil && buildGradleFilePath != "" {
+		var contentsString string
+		contentsString, err = fs.ReadTextFile(buildGradleFilePath)
+		if err == nil {
+			matches := groupLineRegex.FindStringSubmatch(contentsString)
+			if matches != nil {
+				groupId = matches[1]
+			}
+		}
+	}
+
+	return groupId, err
+}
+
+func extractVersionFromGradleProperties(fs utils.FileSystem, folderPath string) (string, string, error) {
+	var version string
+	var versionFilePath string
+
+	gradlePropertiesFilePath, err := findFirstExistingFile(fs, folderPath, GRADLE_PROPERTIES_FILE_NAME)
+	if err == nil && gradlePropertiesFilePath != "" {
+		var contentsString string
+		contentsString, err = fs.ReadTextFile(gradlePropertiesFilePath)
+		if err == nil {
+			matches := gradlePropertiesVersionRegex.FindStringSubmatch(contentsString)
+			if matches != nil {
+				version = matches[1]
+				versionFilePath = gradlePropertiesFilePath
+			}
+		}
+	}
+
+	return version, versionFilePath, err
+}
+
 func extractProjectNameFromGradleSettings(fs utils.FileSystem, folderPath string) (string, error) {
 
 	var projectName string = ""
 
-	gradlePropsFilePath := path.Join(folderPath, "settings.gradle")
-
-	contentsString, err := fs.ReadTextFile(gradlePropsFilePath)
-	if err != nil {
-		if strings.Contains(err.Error(), "no such file") {
+	gradleSettingsFilePath, err := findFirstExistingFile(fs, folderPath, SETTINGS_GRADLE_FILE_NAME, SETTINGS_GRADLE_KTS_FILE_NAME)
+	if err == nil {
+		if gradleSettingsFilePath == "" {
 			log.Printf("Warning: settings.gradle file is not present, so folder %v does not contain a module.\n", folderPath)
-			err = nil
-		}
-	} else {
-		matches := projectNameRegex.FindStringSubmatch(contentsString)
-		if matches == nil {
-			// There was no version in this settings.gradle file. Warning ?
-			log.Printf("Warning: settings.gradle file has no project name, so folder %v does not contain a module.\n", folderPath)
 		} else {
-			// There is a match. We know the project name now.
-			projectName = matches[1]
+			var contentsString string
+			contentsString, err = fs.ReadTextFile(gradleSettingsFilePath)
+			if err == nil {
+				matches := projectNameRegex.FindStringSubmatch(contentsString)
+				if matches == nil {
+					// There was no version in this settings.gradle file. Warning ?
+					log.Printf("Warning: settings.gradle file has no project name, so folder %v does not contain a module.\n", folderPath)
+				} else {
+					// There is a match. We know the project name now.
+					projectName = matches[1]
+				}
+			}
 		}
 	}
 
@@ -156,53 +286,128 @@ func extractProjectNameFromGradleSettings(fs utils.FileSystem, folderPath string
 
 }
 
+// findFirstExistingFile returns the path of the first of the named files which exists in the
+// folder, or "" if none of them exist.
+func findFirstExistingFile(fs utils.FileSystem, folderPath string, fileNames ...string) (string, error) {
+	var err error
+	var foundFilePath string
+
+	for _, fileName := range fileNames {
+		filePath := path.Join(folderPath, fileName)
+
+		var isExisting bool
+		isExisting, err = fs.Exists(filePath)
+		if err != nil || isExisting {
+			if isExisting {
+				foundFilePath = filePath
+			}
+			break
+		}
+	}
+
+	return foundFilePath, err
+}
+
 type ModuleImpl struct {
-	projectName string
-	path        string
-	version     string
+	groupId         string
+	projectName     string
+	path            string
+	version         string
+	versionFilePath string
 }
 
 type Module interface {
 	GetProjectName() string
+
+	// GetArtifactName returns the name the module is published with, which is what release metadata
+	// refers to it by. For a gradle subproject, this is the project name without the root project name.
+	GetArtifactName() string
+
+	// GetGroupId returns the group the module is published with, or "" if it isn't known.
+	GetGroupId() string
+
 	GetPath() string
 	GetVersion() string
+
+	// GetVersionFilePath returns the path of the file which holds the version of this module.
+	GetVersionFilePath() string
+}
+
+// NewModule creates a gradle module, which has its version held in the build.gradle file.
+func NewModule(projectName string, modulePath string, version string) Module {
+	return NewModuleWithVersionFile(projectName, modulePath, version, path.Join(modulePath, "build.gradle"))
 }
 
-func NewModule(projectName string, path string, version string) Module {
+// NewModuleWithVersionFile creates a module which has its version held in the specified file.
+func NewModuleWithVersionFile(projectName string, modulePath string, version string, versionFilePath string) Module {
+	return NewModuleWithGroupId("", projectName, modulePath, version, versionFilePath)
+}
+
+// NewModuleWithGroupId creates a module which is published with a known group, and has its version held in the specified file.
+func NewModuleWithGroupId(groupId string, projectName string, modulePath string, version string, versionFilePath string) Module {
 	module := new(ModuleImpl)
+	module.groupId = groupId
 	module.projectName = projectName
-	module.path = path
+	module.path = modulePath
 	module.version = version
+	module.versionFilePath = versionFilePath
 	return module
 }
 
 func (module *ModuleImpl) GetProjectName() string {
 	return module.projectName
 }
+func (module *ModuleImpl) GetArtifactName() string {
+	return unqualifiedProjectName(module.projectName)
+}
+func (module *ModuleImpl) GetGroupId() string {
+	return module.groupId
+}
 func (module *ModuleImpl) GetPath() string {
 	return module.path
 }
 func (module *ModuleImpl) GetVersion() string {
 	return module.version
 }
+func (module *ModuleImpl) GetVersionFilePath() string {
+	return module.versionFilePath
+}
 
 func gatherEligibleBuildGradleFolders(fs utils.FileSystem, sourceCodeFolderPath string) ([]string, error) {
 	var buildFolders []string = make([]string, 0)
 
-	filePaths, err := fs.GetAllFilePaths(sourceCodeFolderPath)
-	for _, filePath := range filePaths {
-		dirPart, filePart := path.Split(filePath)
-		if "build.gradle" == filePart {
-
-			buildFolders = append(buildFolders, dirPart)
+	buildGradleFilePaths, err := gatherEligibleBuildGradleFiles(fs, sourceCodeFolderPath)
+	if err == nil {
+		isFolderAdded := make(map[string]bool)
+		for _, buildGradleFilePath := range buildGradleFilePaths {
+			dirPart, _ := path.Split(buildGradleFilePath)
+			if !isFolderAdded[dirPart] {
+				isFolderAdded[dirPart] = true
+				buildFolders = append(buildFolders, dirPart)
+			}
 		}
 	}
 
+	return buildFolders, err
+}
+
+// gatherEligibleBuildGradleFiles finds all the groovy build.gradle and kotlin build.gradle.kts
+// files under the source code folder.
+func gatherEligibleBuildGradleFiles(fs utils.FileSystem, sourceCodeFolderPath string) ([]string, error) {
+	var buildFiles []string = make([]string, 0)
+
+	filePaths, err := fs.GetAllFilePaths(sourceCodeFolderPath)
 	if err != nil {
 		log.Printf("impossible to walk directories: %s", err)
 	} else {
 		sort.Strings(filePaths)
+		for _, filePath := range filePaths {
+			_, filePart := path.Split(filePath)
+			if filePart == BUILD_GRADLE_FILE_NAME || filePart == BUILD_GRADLE_KTS_FILE_NAME {
+				buildFiles = append(buildFiles, filePath)
+			}
+		}
 	}
 
-	return buildFolders, err
+	return buildFiles, err
 }
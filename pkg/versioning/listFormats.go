@@ -0,0 +1,113 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	LIST_FORMAT_TEXT = "text"
+	LIST_FORMAT_JSON = "json"
+	LIST_FORMAT_YAML = "yaml"
+	LIST_FORMAT_CSV  = "csv"
+)
+
+// The details of a module which are written out when listing in a machine-readable format.
+type ModuleListItem struct {
+	ProjectName string `json:"projectName" yaml:"projectName"`
+	Version     string `json:"version" yaml:"version"`
+	BaseVersion string `json:"baseVersion" yaml:"baseVersion"`
+	Suffix      string `json:"suffix" yaml:"suffix"`
+	Path        string `json:"path" yaml:"path"`
+}
+
+func validateListFormat(format string) error {
+	var err error
+	switch format {
+	case LIST_FORMAT_TEXT, LIST_FORMAT_JSON, LIST_FORMAT_YAML, LIST_FORMAT_CSV:
+		// It's valid.
+	default:
+		err = fmt.Errorf("Invalid format '%s'. It must be one of '%s', '%s', '%s' or '%s'.",
+			format, LIST_FORMAT_TEXT, LIST_FORMAT_JSON, LIST_FORMAT_YAML, LIST_FORMAT_CSV)
+	}
+	return err
+}
+
+func NewModuleListItem(module Module) ModuleListItem {
+	version := module.GetVersion()
+	baseVersion := calculateDesiredVersion(version, "")
+	return ModuleListItem{
+		ProjectName: module.GetProjectName(),
+		Version:     version,
+		BaseVersion: baseVersion,
+		Suffix:      version[len(baseVersion):],
+		Path:        module.GetPath(),
+	}
+}
+
+// formatModuleListing renders the modules in the requested format.
+func formatModuleListing(modules []Module, format string) (string, error) {
+	var err error
+	var output string
+
+	items := make([]ModuleListItem, 0, len(modules))
+	for _, module := range modules {
+		items = append(items, NewModuleListItem(module))
+	}
+
+	switch format {
+	case LIST_FORMAT_JSON:
+		var bytes []byte
+		bytes, err = json.MarshalIndent(items, "", "  ")
+		if err == nil {
+			output = string(bytes) + "\n"
+		}
+	case LIST_FORMAT_YAML:
+		var bytes []byte
+		bytes, err = yaml.Marshal(items)
+		if err == nil {
+			output = string(bytes)
+		}
+	case LIST_FORMAT_CSV:
+		output, err = formatModuleListingAsCsv(items)
+	default:
+		var builder strings.Builder
+		for _, item := range items {
+			builder.WriteString(fmt.Sprintf("%s %s\n", item.ProjectName, item.Version))
+		}
+		output = builder.String()
+	}
+
+	return output, err
+}
+
+func formatModuleListingAsCsv(items []ModuleListItem) (string, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	err := writer.Write([]string{"projectName", "version", "baseVersion", "suffix", "path"})
+	for _, item := range items {
+		if err != nil {
+			break
+		}
+		err = writer.Write([]string{item.ProjectName, item.Version, item.BaseVersion, item.Suffix, item.Path})
+	}
+
+	if err == nil {
+		writer.Flush()
+		err = writer.Error()
+	}
+
+	return buffer.String(), err
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func createListingModules() []Module {
+	return []Module{
+		NewModule("a.b.c", "/src/a.b.c/", "0.21.0"),
+		NewModule("a.b.d", "/src/a.b.d/", "0.25.0-SNAPSHOT"),
+	}
+}
+
+func TestListFailsIfFormatIsInvalid(t *testing.T) {
+	err := ListExecute(nil, "", "xml")
+	assert.NotNil(t, err)
+}
+
+func TestCanFormatListingAsText(t *testing.T) {
+	output, err := formatModuleListing(createListingModules(), LIST_FORMAT_TEXT)
+	assert.Nil(t, err)
+	assert.Equal(t, "a.b.c 0.21.0\na.b.d 0.25.0-SNAPSHOT\n", output)
+}
+
+func TestCanFormatListingAsJson(t *testing.T) {
+	output, err := formatModuleListing(createListingModules(), LIST_FORMAT_JSON)
+	assert.Nil(t, err)
+
+	var items []ModuleListItem
+	err = json.Unmarshal([]byte(output), &items)
+	assert.Nil(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, ModuleListItem{
+		ProjectName: "a.b.d",
+		Version:     "0.25.0-SNAPSHOT",
+		BaseVersion: "0.25.0",
+		Suffix:      "-SNAPSHOT",
+		Path:        "/src/a.b.d/",
+	}, items[1])
+}
+
+func TestCanFormatListingAsYaml(t *testing.T) {
+	output, err := formatModuleListing(createListingModules(), LIST_FORMAT_YAML)
+	assert.Nil(t, err)
+
+	var items []ModuleListItem
+	err = yaml.Unmarshal([]byte(output), &items)
+	assert.Nil(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, "a.b.c", items[0].ProjectName)
+	assert.Equal(t, "0.21.0", items[0].BaseVersion)
+	assert.Equal(t, "", items[0].Suffix)
+}
+
+func TestCanFormatListingAsCsv(t *testing.T) {
+	output, err := formatModuleListing(createListingModules(), LIST_FORMAT_CSV)
+	assert.Nil(t, err)
+	assert.Equal(t, "projectName,version,baseVersion,suffix,path\n"+
+		"a.b.c,0.21.0,0.21.0,,/src/a.b.c/\n"+
+		"a.b.d,0.25.0-SNAPSHOT,0.25.0,-SNAPSHOT,/src/a.b.d/\n", output)
+}
@@ -92,6 +92,17 @@ func TestCanFindTwoModules(t *testing.T) {
 	assert.Equal(t, modules[1].GetVersion(), "0.36.0-dev")
 }
 
+func TestGradleModuleHasTheGroupFromItsBuildFile(t *testing.T) {
+	fs := createTwoModuleFs()
+	fs.WriteTextFile("/my/random/folder/module1/build.gradle", `group = 'dev.galasa'
+version = "0.36.0-SNAPSHOT"`)
+
+	modules, err := getModules(fs, "/my")
+	assert.Nil(t, err)
+	assert.Equal(t, "dev.galasa", modules[0].GetGroupId())
+	assert.Equal(t, "", modules[1].GetGroupId())
+}
+
 func TestVersionRegexMatchesExampleDoubleQuotes(t *testing.T) {
 	matches := versionLineRegex.FindStringSubmatch(`  version= "12.13.24-dev"`)
 	assert.NotNil(t, matches)
@@ -186,3 +197,70 @@ dependencies {
 	assert.Len(t, matches, 2)
 	assert.Equal(t, matches[1], "0.34.0")
 }
+
+func createKotlinAndPropertiesModuleFs() *utils.MockFileSystem {
+	fs := utils.NewOverridableMockFileSystem()
+
+	// A module using the kotlin DSL.
+	fs.MkdirAll("/my/kotlin/module")
+	fs.WriteTextFile("/my/kotlin/module/build.gradle.kts",
+		`plugins {
+    id("galasa.manager")
+}
+
+version = "0.36.0-SNAPSHOT"
+`)
+	fs.WriteTextFile("/my/kotlin/module/settings.gradle.kts",
+		`rootProject.name = "my.kotlin.module"`)
+
+	// A module with its version in the gradle.properties file.
+	fs.MkdirAll("/my/properties/module")
+	fs.WriteTextFile("/my/properties/module/build.gradle",
+		`version = project.findProperty("version")`)
+	fs.WriteTextFile("/my/properties/module/gradle.properties",
+		`# The version of this module.
+org.gradle.caching=true
+version=0.36.0-dev
+`)
+	fs.WriteTextFile("/my/properties/module/settings.gradle",
+		`rootProject.name = 'my.properties.module'`)
+	return fs
+}
+
+func TestCanFindKotlinAndGradlePropertiesModules(t *testing.T) {
+	fs := createKotlinAndPropertiesModuleFs()
+
+	modules, err := getModules(fs, "/my")
+	assert.Nil(t, err)
+	assert.Len(t, modules, 2)
+
+	assert.Equal(t, "my.kotlin.module", modules[0].GetProjectName())
+	assert.Equal(t, "0.36.0-SNAPSHOT", modules[0].GetVersion())
+	assert.Equal(t, "/my/kotlin/module/build.gradle.kts", modules[0].GetVersionFilePath())
+
+	assert.Equal(t, "my.properties.module", modules[1].GetProjectName())
+	assert.Equal(t, "0.36.0-dev", modules[1].GetVersion())
+	assert.Equal(t, "/my/properties/module/gradle.properties", modules[1].GetVersionFilePath())
+}
+
+func TestSuffixSetWritesToFileHoldingVersion(t *testing.T) {
+	fs := createKotlinAndPropertiesModuleFs()
+
+	err := SuffixSetExecute(fs, "/my", "-alpha", "")
+	assert.Nil(t, err)
+
+	kotlinContents, _ := fs.ReadTextFile("/my/kotlin/module/build.gradle.kts")
+	assert.Contains(t, kotlinContents, `version = "0.36.0-alpha"`)
+
+	propertiesContents, _ := fs.ReadTextFile("/my/properties/module/gradle.properties")
+	assert.Contains(t, propertiesContents, "version=0.36.0-alpha\n")
+
+	buildGradleContents, _ := fs.ReadTextFile("/my/properties/module/build.gradle")
+	assert.Equal(t, `version = project.findProperty("version")`, buildGradleContents)
+}
+
+func TestGradlePropertiesVersionRegexMatchesColonSeparator(t *testing.T) {
+	matches := gradlePropertiesVersionRegex.FindStringSubmatch("version : 1.2.3")
+	assert.NotNil(t, matches)
+	assert.Equal(t, "1.2.3", matches[1])
+}
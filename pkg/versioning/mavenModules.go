@@ -0,0 +1,290 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+)
+
+const POM_FILE_NAME = "pom.xml"
+
+// The parts of a pom.xml file which we need in order to work out the identity of a maven module.
+type pomProject struct {
+	GroupId    string    `xml:"groupId"`
+	ArtifactId string    `xml:"artifactId"`
+	Version    string    `xml:"version"`
+	Parent     pomParent `xml:"parent"`
+}
+
+type pomParent struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
+// gatherEligiblePomFiles finds all the pom.xml files under the source code folder, ignoring any
+// which are in folders already known to be gradle modules, or which are maven build output.
+func gatherEligiblePomFiles(fs utils.FileSystem, sourceCodeFolderPath string, buildGradleFolderPaths []string) ([]string, error) {
+	var pomFilePaths []string = make([]string, 0)
+
+	gradleFolders := make(map[string]bool)
+	for _, buildGradleFolderPath := range buildGradleFolderPaths {
+		gradleFolders[buildGradleFolderPath] = true
+	}
+
+	filePaths, err := fs.GetAllFilePaths(sourceCodeFolderPath)
+	if err != nil {
+		log.Printf("impossible to walk directories: %s", err)
+	} else {
+		for _, filePath := range filePaths {
+			dirPart, filePart := path.Split(filePath)
+			if filePart == POM_FILE_NAME && !gradleFolders[dirPart] && !isInMavenBuildOutput(dirPart) {
+				pomFilePaths = append(pomFilePaths, filePath)
+			}
+		}
+		sort.Strings(pomFilePaths)
+	}
+
+	return pomFilePaths, err
+}
+
+// Maven copies pom.xml files into the target folder when it packages things, so those aren't modules.
+func isInMavenBuildOutput(folderPath string) bool {
+	isBuildOutput := false
+	for _, folderName := range strings.Split(folderPath, "/") {
+		if folderName == "target" {
+			isBuildOutput = true
+			break
+		}
+	}
+	return isBuildOutput
+}
+
+// A pom.xml file which holds the version of a module.
+type pomModuleFile struct {
+	filePath   string
+	groupId    string
+	artifactId string
+	version    string
+
+	// The groupId:artifactId of the <parent>, if the version is inherited from it.
+	versionParent string
+}
+
+// extractModulesFromPomFiles finds the modules held in the pom.xml files.
+//
+// A pom which inherits its version from its <parent> is only a module if the parent is one too.
+// Otherwise the version belongs to a parent which isn't built from this source tree, such as a
+// third-party parent pom, and changing it would refer to a version of the parent which doesn't exist.
+func extractModulesFromPomFiles(fs utils.FileSystem, pomFilePaths []string) ([]Module, error) {
+	var err error
+	var modules []Module = make([]Module, 0)
+	var pomFiles []pomModuleFile
+
+	for _, pomFilePath := range pomFilePaths {
+		var pomFile *pomModuleFile
+
+		pomFile, err = readPomModuleFile(fs, pomFilePath)
+		if err != nil {
+			log.Printf("Error extracting the module from pom file. %v", err)
+			break
+		}
+
+		if pomFile != nil {
+			pomFiles = append(pomFiles, *pomFile)
+		}
+	}
+
+	if err == nil {
+		pomFiles = removePomsWithExternalVersionParents(pomFiles)
+		for _, pomFile := range pomFiles {
+			log.Printf("Found maven module %s:%s:%s\n", pomFile.groupId, pomFile.artifactId, pomFile.version)
+			dirPart, _ := path.Split(pomFile.filePath)
+			modules = append(modules, NewModuleWithGroupId(pomFile.groupId, pomFile.artifactId, dirPart, pomFile.version, pomFile.filePath))
+		}
+	}
+
+	return modules, err
+}
+
+// readPomModuleFile reads the coordinates of the module in a pom.xml file, or returns nil if it
+// does not contain a module.
+func readPomModuleFile(fs utils.FileSystem, pomFilePath string) (*pomModuleFile, error) {
+	var pomFile *pomModuleFile
+
+	contentsString, err := fs.ReadTextFile(pomFilePath)
+	if err == nil {
+		var project pomProject
+		project, err = parsePom(contentsString)
+		if err != nil {
+			err = fmt.Errorf("failed to parse maven file %s - %s", pomFilePath, err.Error())
+		} else {
+			groupId, artifactId, version := project.resolveCoordinates()
+
+			if artifactId == "" || version == "" {
+				log.Printf("Warning: pom.xml file %s has no artifactId or version, so it does not contain a module.\n", pomFilePath)
+			} else if strings.Contains(version, "${") {
+				log.Printf("Warning: pom.xml file %s has a version '%s' which uses a property, so it is not treated as a module.\n", pomFilePath, version)
+			} else {
+				pomFile = &pomModuleFile{filePath: pomFilePath, groupId: groupId, artifactId: artifactId, version: version}
+				if strings.TrimSpace(project.Version) == "" {
+					pomFile.versionParent = strings.TrimSpace(project.Parent.GroupId) + ":" + strings.TrimSpace(project.Parent.ArtifactId)
+				}
+			}
+		}
+	}
+
+	return pomFile, err
+}
+
+// removePomsWithExternalVersionParents drops the poms which inherit their version from a parent
+// which isn't one of the other modules. Removing one can leave its own children without a module
+// parent, so this carries on until no more are removed.
+func removePomsWithExternalVersionParents(pomFiles []pomModuleFile) []pomModuleFile {
+	isRemoving := true
+	for isRemoving {
+		isRemoving = false
+
+		moduleCoordinates := make(map[string]bool)
+		for _, pomFile := range pomFiles {
+			moduleCoordinates[pomFile.groupId+":"+pomFile.artifactId] = true
+		}
+
+		var keptPomFiles []pomModuleFile
+		for _, pomFile := range pomFiles {
+			if pomFile.versionParent != "" && !moduleCoordinates[pomFile.versionParent] {
+				log.Printf("Warning: pom.xml file %s inherits its version from parent %s, which is not a module in the source tree,"+
+					" so it is not treated as a module.\n", pomFile.filePath, pomFile.versionParent)
+				isRemoving = true
+			} else {
+				keptPomFiles = append(keptPomFiles, pomFile)
+			}
+		}
+		pomFiles = keptPomFiles
+	}
+	return pomFiles
+}
+
+func parsePom(contents string) (pomProject, error) {
+	var project pomProject
+	err := xml.Unmarshal([]byte(contents), &project)
+	return project, err
+}
+
+// resolveCoordinates works out the groupId:artifactId:version of a maven project, where the
+// groupId and version are inherited from the <parent> if the project doesn't declare them itself.
+func (project pomProject) resolveCoordinates() (string, string, string) {
+	groupId := strings.TrimSpace(project.GroupId)
+	if groupId == "" {
+		groupId = strings.TrimSpace(project.Parent.GroupId)
+	}
+
+	version := strings.TrimSpace(project.Version)
+	if version == "" {
+		version = strings.TrimSpace(project.Parent.Version)
+	}
+
+	return groupId, strings.TrimSpace(project.ArtifactId), version
+}
+
+// substitutedPomVersion re-writes the version held in a pom.xml file. If the module inherits
+// its version from its parent, then the version of the parent reference is the one replaced.
+func substitutedPomVersion(fs utils.FileSystem, module Module, desiredVersion string) error {
+
+	pomFilePath := module.GetVersionFilePath()
+	pomFileContents, err := fs.ReadTextFile(pomFilePath)
+
+	if err == nil {
+		var startIndex, endIndex int
+		startIndex, endIndex, err = findPomVersionIndexes(pomFileContents)
+		if err != nil {
+			err = fmt.Errorf("failed to find the version in maven file %s - %s", pomFilePath, err.Error())
+		} else {
+			beforeMatch := pomFileContents[:startIndex]
+			afterMatch := pomFileContents[endIndex:]
+
+			contentAfterSubstitution := beforeMatch + desiredVersion + afterMatch
+
+			err = fs.WriteTextFile(pomFilePath, contentAfterSubstitution)
+		}
+	}
+
+	return err
+}
+
+// findPomVersionIndexes finds the start and end offsets of the text holding the project version
+// in the contents of a pom.xml file. The <project><version> is preferred, falling back to the
+// <project><parent><version> if the project has no version of its own. Poms are only modules in that
+// case if the parent is a module too, so it's the version of one of our own modules which is changed.
+func findPomVersionIndexes(contents string) (int, int, error) {
+	var err error
+	projectVersionStart, projectVersionEnd := -1, -1
+	parentVersionStart, parentVersionEnd := -1, -1
+
+	decoder := xml.NewDecoder(strings.NewReader(contents))
+	var elementPath []string
+
+	for {
+		tokenStart := int(decoder.InputOffset())
+
+		var token xml.Token
+		token, err = decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			break
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			elementPath = append(elementPath, element.Name.Local)
+		case xml.EndElement:
+			elementPath = elementPath[:len(elementPath)-1]
+		case xml.CharData:
+			tokenEnd := int(decoder.InputOffset())
+			start, end := trimmedIndexes(contents, tokenStart, tokenEnd)
+			currentPath := strings.Join(elementPath, "/")
+			if currentPath == "project/version" {
+				projectVersionStart, projectVersionEnd = start, end
+			} else if currentPath == "project/parent/version" {
+				parentVersionStart, parentVersionEnd = start, end
+			}
+		}
+	}
+
+	startIndex, endIndex := projectVersionStart, projectVersionEnd
+	if startIndex == -1 {
+		startIndex, endIndex = parentVersionStart, parentVersionEnd
+	}
+
+	if err == nil && startIndex == -1 {
+		err = errors.New("no project or parent version element")
+	}
+
+	return startIndex, endIndex, err
+}
+
+// trimmedIndexes narrows a range of the contents so it excludes leading and trailing white space.
+func trimmedIndexes(contents string, start int, end int) (int, int) {
+	for start < end && strings.ContainsRune(" \t\r\n", rune(contents[start])) {
+		start++
+	}
+	for end > start && strings.ContainsRune(" \t\r\n", rune(contents[end-1])) {
+		end--
+	}
+	return start, end
+}
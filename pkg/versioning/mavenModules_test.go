@@ -0,0 +1,194 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+
+package versioning
+
+import (
+	"testing"
+
+	"galasa.dev/buildUtilities/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMixedGradleAndMavenFs() *utils.MockFileSystem {
+	fs := createTwoModuleFs()
+
+	// A maven parent module
+	fs.MkdirAll("/my/maven/parent")
+	fs.WriteTextFile("/my/maven/parent/pom.xml",
+		`<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+	<modelVersion>4.0.0</modelVersion>
+	<groupId>dev.galasa</groupId>
+	<artifactId>dev.galasa.maven.parent</artifactId>
+	<version>0.36.0</version>
+	<packaging>pom</packaging>
+</project>`)
+
+	// A maven module which inherits its group and version from the parent.
+	fs.MkdirAll("/my/maven/parent/child")
+	fs.WriteTextFile("/my/maven/parent/child/pom.xml",
+		`<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+	<modelVersion>4.0.0</modelVersion>
+	<parent>
+		<groupId>dev.galasa</groupId>
+		<artifactId>dev.galasa.maven.parent</artifactId>
+		<version>0.36.0</version>
+	</parent>
+	<artifactId>dev.galasa.maven.child</artifactId>
+	<dependencies>
+		<dependency>
+			<groupId>dev.galasa</groupId>
+			<artifactId>dev.galasa.other</artifactId>
+			<version>1.2.3</version>
+		</dependency>
+	</dependencies>
+</project>`)
+
+	// Build output which should be ignored.
+	fs.MkdirAll("/my/maven/parent/child/target/classes/META-INF/maven")
+	fs.WriteTextFile("/my/maven/parent/child/target/classes/META-INF/maven/pom.xml",
+		`<project><artifactId>dev.galasa.maven.child</artifactId><version>0.36.0</version></project>`)
+	return fs
+}
+
+func TestCanFindMavenAndGradleModules(t *testing.T) {
+	fs := createMixedGradleAndMavenFs()
+
+	modules, err := getModules(fs, "/my")
+
+	assert.Nil(t, err)
+	assert.Len(t, modules, 4)
+
+	assert.Equal(t, "dev.galasa.maven.child", modules[0].GetProjectName())
+	assert.Equal(t, "/my/maven/parent/child/", modules[0].GetPath())
+	assert.Equal(t, "0.36.0", modules[0].GetVersion())
+	assert.Equal(t, "/my/maven/parent/child/pom.xml", modules[0].GetVersionFilePath())
+	assert.Equal(t, "dev.galasa", modules[0].GetGroupId())
+
+	assert.Equal(t, "dev.galasa.maven.parent", modules[1].GetProjectName())
+	assert.Equal(t, "0.36.0", modules[1].GetVersion())
+
+	assert.Equal(t, "my.random.folder.module1", modules[2].GetProjectName())
+	assert.Equal(t, "/my/random/folder/module1/build.gradle", modules[2].GetVersionFilePath())
+	assert.Equal(t, "", modules[2].GetGroupId())
+	assert.Equal(t, "my.random.folder.module3", modules[3].GetProjectName())
+}
+
+func TestPomInheritsGroupAndVersionFromParent(t *testing.T) {
+	project, err := parsePom(`<project>
+		<parent><groupId>a.b</groupId><artifactId>parent</artifactId><version>1.0.0</version></parent>
+		<artifactId>child</artifactId>
+	</project>`)
+	assert.Nil(t, err)
+
+	groupId, artifactId, version := project.resolveCoordinates()
+	assert.Equal(t, "a.b", groupId)
+	assert.Equal(t, "child", artifactId)
+	assert.Equal(t, "1.0.0", version)
+}
+
+func TestPomOwnVersionOverridesParent(t *testing.T) {
+	project, err := parsePom(`<project>
+		<parent><groupId>a.b</groupId><artifactId>parent</artifactId><version>1.0.0</version></parent>
+		<groupId>c.d</groupId>
+		<artifactId>child</artifactId>
+		<version>2.0.0</version>
+	</project>`)
+	assert.Nil(t, err)
+
+	groupId, artifactId, version := project.resolveCoordinates()
+	assert.Equal(t, "c.d", groupId)
+	assert.Equal(t, "child", artifactId)
+	assert.Equal(t, "2.0.0", version)
+}
+
+func TestIgnoresPomWithPropertyVersion(t *testing.T) {
+	fs := utils.NewOverridableMockFileSystem()
+	fs.MkdirAll("/my/module")
+	fs.WriteTextFile("/my/module/pom.xml",
+		`<project><artifactId>a</artifactId><version>${revision}</version></project>`)
+
+	modules, err := getModules(fs, "/my")
+	assert.Nil(t, err)
+	assert.Len(t, modules, 0)
+}
+
+func TestIgnoresPomInheritingVersionFromExternalParent(t *testing.T) {
+	fs := createMixedGradleAndMavenFs()
+	fs.MkdirAll("/my/maven/external")
+	fs.WriteTextFile("/my/maven/external/pom.xml", `<project>
+		<parent><groupId>org.springframework.boot</groupId><artifactId>spring-boot-starter-parent</artifactId><version>3.2.0</version></parent>
+		<artifactId>dev.galasa.maven.external</artifactId>
+	</project>`)
+
+	// A child of the ignored pom inherits an external version too.
+	fs.MkdirAll("/my/maven/external/child")
+	fs.WriteTextFile("/my/maven/external/child/pom.xml", `<project>
+		<parent><groupId>org.springframework.boot</groupId><artifactId>dev.galasa.maven.external</artifactId><version>3.2.0</version></parent>
+		<artifactId>dev.galasa.maven.external.child</artifactId>
+	</project>`)
+
+	modules, err := getModules(fs, "/my")
+	assert.Nil(t, err)
+	assert.Len(t, modules, 4)
+	for _, module := range modules {
+		assert.NotContains(t, module.GetProjectName(), "external")
+	}
+
+	err = SuffixSetExecute(fs, "/my", "-alpha", "")
+	assert.Nil(t, err)
+	externalPom, _ := fs.ReadTextFile("/my/maven/external/pom.xml")
+	assert.Contains(t, externalPom, "<version>3.2.0</version>")
+}
+
+func TestPomWithOwnVersionAndExternalParentIsAModule(t *testing.T) {
+	fs := utils.NewOverridableMockFileSystem()
+	fs.MkdirAll("/my/module")
+	fs.WriteTextFile("/my/module/pom.xml", `<project>
+		<parent><groupId>org.example</groupId><artifactId>parent</artifactId><version>3.2.0</version></parent>
+		<groupId>dev.galasa</groupId>
+		<artifactId>dev.galasa.module</artifactId>
+		<version>0.36.0</version>
+	</project>`)
+
+	modules, err := getModules(fs, "/my")
+	assert.Nil(t, err)
+	assert.Len(t, modules, 1)
+	assert.Equal(t, "0.36.0", modules[0].GetVersion())
+}
+
+func TestCanSubstituteVersionsInMavenAndGradleModules(t *testing.T) {
+	mockFs := createMixedGradleAndMavenFs()
+	err := SuffixSetExecute(mockFs, "/my", "-alpha", "")
+	assert.Nil(t, err)
+
+	modules, err := getModules(mockFs, "/my")
+	assert.Nil(t, err)
+	assert.Len(t, modules, 4)
+	for _, module := range modules {
+		assert.Equal(t, "0.36.0-alpha", module.GetVersion())
+	}
+
+	// The dependency version in the child must not have been touched.
+	childPom, _ := mockFs.ReadTextFile("/my/maven/parent/child/pom.xml")
+	assert.Contains(t, childPom, "<version>1.2.3</version>")
+	assert.Contains(t, childPom, "<version>0.36.0-alpha</version>")
+}
+
+func TestCanRemoveSuffixFromMavenModule(t *testing.T) {
+	mockFs := utils.NewOverridableMockFileSystem()
+	mockFs.MkdirAll("/my/module")
+	mockFs.WriteTextFile("/my/module/pom.xml",
+		"<project>\n  <artifactId>a</artifactId>\n  <version>\n    1.0.0-SNAPSHOT\n  </version>\n</project>")
+
+	err := SuffixRemoveExecute(mockFs, "/my", "")
+	assert.Nil(t, err)
+
+	pom, _ := mockFs.ReadTextFile("/my/module/pom.xml")
+	assert.Equal(t, "<project>\n  <artifactId>a</artifactId>\n  <version>\n    1.0.0\n  </version>\n</project>", pom)
+}
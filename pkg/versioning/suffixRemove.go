@@ -10,13 +10,21 @@ import (
 	"galasa.dev/buildUtilities/pkg/utils"
 )
 
-func SuffixRemoveExecute(fs utils.FileSystem, sourceCodeFolderPath string) error {
+// SuffixRemoveExecute removes the suffix from every module version in the source folder, and
+// from any dependencies on those modules, in the same all-or-nothing way as SuffixSetExecute.
+func SuffixRemoveExecute(fs utils.FileSystem, sourceCodeFolderPath string, journalFilePath string) error {
 	var err error
 
 	var modules []Module
 	modules, err = getModules(fs, sourceCodeFolderPath)
 	if err == nil {
-		err = setSuffixOnAllModules(fs, modules, "")
+		err = runTransaction(fs, journalFilePath, func(stagingFs utils.FileSystem) error {
+			err := setSuffixOnAllModules(stagingFs, modules, "")
+			if err == nil {
+				err = setSuffixOnDependencyReferences(stagingFs, sourceCodeFolderPath, modules, "")
+			}
+			return err
+		})
 	}
 
 	return err
@@ -14,7 +14,7 @@ import (
 
 func TestCanSubstituteVersionsForBlankSuffix(t *testing.T) {
 	mockFs := createTwoModuleFs()
-	err := SuffixRemoveExecute(mockFs, "/my")
+	err := SuffixRemoveExecute(mockFs, "/my", "")
 	assert.Nil(t, err)
 
 	// Now get the versions out again.
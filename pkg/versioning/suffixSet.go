@@ -8,13 +8,19 @@ package versioning
 
 import (
 	"errors"
+	"fmt"
 	"path"
+	"regexp"
 	"strings"
 
 	"galasa.dev/buildUtilities/pkg/utils"
 )
 
-func SuffixSetExecute(fs utils.FileSystem, sourceCodeFolderPath string, desiredSuffix string) error {
+// SuffixSetExecute sets the suffix of every module version in the source folder, and of any
+// dependencies on those modules. No files are changed unless all the changes can be made. The
+// original contents of the changed files are written to the journal file, or to a temporary
+// folder if the journal file path is blank, so the changes can be undone.
+func SuffixSetExecute(fs utils.FileSystem, sourceCodeFolderPath string, desiredSuffix string, journalFilePath string) error {
 	var err error
 
 	err = validateSuffix(desiredSuffix)
@@ -23,7 +29,13 @@ func SuffixSetExecute(fs utils.FileSystem, sourceCodeFolderPath string, desiredS
 		var modules []Module
 		modules, err = getModules(fs, sourceCodeFolderPath)
 		if err == nil {
-			err = setSuffixOnAllModules(fs, modules, desiredSuffix)
+			err = runTransaction(fs, journalFilePath, func(stagingFs utils.FileSystem) error {
+				err := setSuffixOnAllModules(stagingFs, modules, desiredSuffix)
+				if err == nil {
+					err = setSuffixOnDependencyReferences(stagingFs, sourceCodeFolderPath, modules, desiredSuffix)
+				}
+				return err
+			})
 		}
 	}
 
@@ -49,33 +61,59 @@ func setSuffixOnAllModules(fs utils.FileSystem, modules []Module, desiredSuffix
 		currentVersion := module.GetVersion()
 		var desiredVersion string
 		desiredVersion = calculateDesiredVersion(currentVersion, desiredSuffix)
+		err = substituteModuleVersion(fs, module, desiredVersion)
+		if err != nil {
+			break
+		}
+	}
+	return err
+}
+
+// substituteModuleVersion re-writes the version of a module in whichever file holds it.
+func substituteModuleVersion(fs utils.FileSystem, module Module, desiredVersion string) error {
+	var err error
+	switch path.Base(module.GetVersionFilePath()) {
+	case POM_FILE_NAME:
+		err = substitutedPomVersion(fs, module, desiredVersion)
+	case GRADLE_PROPERTIES_FILE_NAME:
+		err = substituteVersionMatchingRegex(fs, module.GetVersionFilePath(), gradlePropertiesVersionRegex, desiredVersion)
+	default:
 		err = substitutedBuildGradleVersion(fs, module, desiredVersion)
 	}
 	return err
 }
 
+// substitutedBuildGradleVersion re-writes the version line of a groovy or kotlin build file.
 func substitutedBuildGradleVersion(fs utils.FileSystem, module Module, desiredVersion string) error {
+	return substituteVersionMatchingRegex(fs, module.GetVersionFilePath(), versionLineRegex, desiredVersion)
+}
 
-	buildGradleFilePath := path.Join(module.GetPath(), "build.gradle")
-	buildGradleFileContents, err := fs.ReadTextFile(buildGradleFilePath)
+// substituteVersionMatchingRegex re-writes the first sub-match of the regex in a file with the desired version.
+func substituteVersionMatchingRegex(fs utils.FileSystem, filePath string, regex *regexp.Regexp, desiredVersion string) error {
+
+	fileContents, err := fs.ReadTextFile(filePath)
 
 	if err == nil {
-		matches := versionLineRegex.FindStringSubmatchIndex(buildGradleFileContents)
+		matches := regex.FindStringSubmatchIndex(fileContents)
 
-		// matches[0] is the start of the whole string
-		// matches[1] is the end of the whole string
-		// matches[2] is the start of the versions part which needs replacing.
-		// matches[3] is the end of the versions part which needs replacing.
+		if matches == nil {
+			err = fmt.Errorf("failed to find the version in file %s", filePath)
+		} else {
+			// matches[0] is the start of the whole string
+			// matches[1] is the end of the whole string
+			// matches[2] is the start of the versions part which needs replacing.
+			// matches[3] is the end of the versions part which needs replacing.
 
-		startIndex := matches[2]
-		endIndex := matches[3]
+			startIndex := matches[2]
+			endIndex := matches[3]
 
-		beforeMatch := buildGradleFileContents[:startIndex]
-		afterMatch := buildGradleFileContents[endIndex:]
+			beforeMatch := fileContents[:startIndex]
+			afterMatch := fileContents[endIndex:]
 
-		contentAfterSubstiitution := beforeMatch + desiredVersion + afterMatch
+			contentAfterSubstiitution := beforeMatch + desiredVersion + afterMatch
 
-		err = fs.WriteTextFile(buildGradleFilePath, contentAfterSubstiitution)
+			err = fs.WriteTextFile(filePath, contentAfterSubstiitution)
+		}
 	}
 
 	return err
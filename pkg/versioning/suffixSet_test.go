@@ -33,13 +33,13 @@ func TestCanCalculateVersionCanReplaceSuffixWhichHasTwoSuffixesAlready(t *testin
 }
 
 func TestSetFailsIfSuffixIsInvalid(t *testing.T) {
-	err := SuffixSetExecute(nil, "", "notvalid")
+	err := SuffixSetExecute(nil, "", "notvalid", "")
 	assert.NotNil(t, err)
 }
 
 func TestCanSubstituteVersions(t *testing.T) {
 	mockFs := createTwoModuleFs()
-	err := SuffixSetExecute(mockFs, "/my", "-alpha")
+	err := SuffixSetExecute(mockFs, "/my", "-alpha", "")
 	assert.Nil(t, err)
 
 	// Now get the versions out again.